@@ -36,6 +36,11 @@ type App struct {
   activeFrame int
   quitPending bool
 
+  scale         float64 // logical pixels to physical pixels, includes zoom
+  scaleOverride float64 // 0.0 -> detect from display dpi
+  zoom          float64
+  direction     Direction
+
   ctx    sdl.GLContext
   debug  *os.File
 }
@@ -82,6 +87,8 @@ func NewApp(name string, skin Skin, glyphs map[string]*Glyph, nFrames int) {
     frames,
     0,
     false,
+    1.0, 0.0, 1.0,
+    LTR,
     nil,
     debug,
   }
@@ -179,7 +186,8 @@ func (app *App) run() error {
     return err
   }
 
-  app.window.SetMinimumSize(1024, 764)
+  // also sets the minimum size
+  app.syncScale()
 
   defer app.window.Destroy()
 
@@ -116,9 +116,7 @@ func (app *App) DrawIfDirty() {
     }
 
     if frame.posDirty() {
-      frame.CalcDepth()
-
-      frame.CalcPos()
+      frame.calcLayout()
 
       // TODO: how should this work for upper frames?
       if app.mouseInWindow() {
@@ -227,6 +225,9 @@ func (app *App) drawFrame(frame *Frame) {
   x, y := frame.GetPos()
   w, h := frame.GetSize()
 
+  // frame works with logical pixels
+  x, y, w, h = app.toPhysical(x), app.toPhysical(y), app.toPhysical(w), app.toPhysical(h)
+
   applyScissor := w < winW || h < winH || x > 0 || y > 0
 
   if applyScissor {
@@ -269,6 +270,9 @@ func (app *App) drawFrame(frame *Frame) {
   checkGLError()
   gl.UseProgram(app.programs.glyphPass)
 
+  // coverage is calculated per physical pixel
+  gl.Uniform1f(int32(app.programs.glyphPass_uScaleLoc), float32(app.scale))
+
   checkGLError()
   frame.P2.SyncAndBind()
 
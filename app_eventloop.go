@@ -16,6 +16,20 @@ type animationEvent struct {
   tick  uint64
 }
 
+// runs fn on the main event loop
+type callbackEvent struct {
+  fn func()
+}
+
+// can be called from any goroutine, including the main event loop itself
+func postCallback(fn func()) {
+  app := getApp()
+
+  go func() {
+    app.eventCh <- &callbackEvent{fn}
+  }()
+}
+
 // runs on main loop, must handle quit, and wm events
 func (app *App) forwardSystemAndUserEvents() error {
   running := true
@@ -59,6 +73,9 @@ func (app *App) syncWindowSize() {
 
   app.winW, app.winH = w, h
 
+  // frames work with logical pixels
+  w, h = app.toLogical(w), app.toLogical(h)
+
   for i, frame := range app.frames {
     if i == 0 {
       frame.maxW, frame.maxH = w, h
@@ -122,18 +139,26 @@ func (app *App) initMainEventLoop(m *sync.Mutex) {
 
     frame := app.ActiveFrame()
 
+    if sdlEvent, ok := event_.(sdl.Event); ok {
+      app.scaleMouseEvent(sdlEvent)
+    }
+
     switch event := event_.(type) {
     case *animationEvent:
       app.onTick(event)
+    case *callbackEvent:
+      event.fn()
     case *sdl.MouseMotionEvent:
       app.onMouseMove(event)
     case *sdl.MouseButtonEvent:
+      frame.state.altAlone = false
+
       if frame.state.blockNextMouseButtonEvent {
         frame.state.blockNextMouseButtonEvent = false
       } else {
         evt := NewMouseEvent(int(event.X), int(event.Y))
 
-        if frame.Menu.Visible() && !frame.Menu.IsHit(int(event.X), int(event.Y)) && !frame.Menu.IsOwnedBy(frame.state.mouseElement) {
+        if frame.Menu.Visible() && frame.menuAt(int(event.X), int(event.Y)) == nil && !frame.Menu.IsOwnedBy(frame.state.mouseElement) {
           if hasEvent(frame.Menu.anchor, "mousebuttonoutsidemenu") {
             TriggerEvent(frame.Menu.anchor, "mousebuttonoutsidemenu", evt)
           } else {
@@ -156,13 +181,33 @@ func (app *App) initMainEventLoop(m *sync.Mutex) {
     case *sdl.TextInputEvent:
       app.onTextInput(event)
     case *sdl.KeyboardEvent:
-      // tab and shift-tab cycle through the focusable elements
-      if event.Keysym.Sym == sdl.K_TAB && event.State == sdl.PRESSED {
+      isAlt := event.Keysym.Sym == sdl.K_LALT || event.Keysym.Sym == sdl.K_RALT
+      if !isAlt {
+        frame.state.altAlone = false
+      }
+
+      // ctrl-tab and ctrl-shift-tab cycle through the tabs of a Tabbed
+      if event.Keysym.Sym == sdl.K_TAB && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) {
+        app.onCtrlTab(event)
+      } else if event.Keysym.Sym == sdl.K_w && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) {
+        app.onCtrlW(event)
+      } else if event.Keysym.Sym == sdl.K_TAB && event.State == sdl.PRESSED {
+        // tab and shift-tab cycle through the focusable elements
         app.onTab(event)
       } else if event.Keysym.Sym == sdl.K_F4 && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_ALT > 0) {
         Quit() // which throws another event!
       } else if event.Keysym.Sym == sdl.K_q && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) {
         Quit() // which throws another event!
+      } else if isZoomInKey(event.Keysym.Sym) && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) {
+        ZoomIn()
+      } else if isZoomOutKey(event.Keysym.Sym) && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) {
+        ZoomOut()
+      } else if isZoomResetKey(event.Keysym.Sym) && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) {
+        ResetZoom()
+      } else if event.Keysym.Sym == sdl.K_d && event.State == sdl.PRESSED && (event.Keysym.Mod & sdl.KMOD_CTRL > 0) && (event.Keysym.Mod & sdl.KMOD_SHIFT > 0) {
+        ToggleDebugOverlay()
+      } else if isAlt {
+        app.onAlt(event)
       } else {
         app.onKeyPress(event)
       }
@@ -237,12 +282,13 @@ func (app *App) onMouseWheel(event *sdl.MouseWheelEvent) {
 func (app *App) onMouseDown(event *sdl.MouseButtonEvent) {
   frame := app.ActiveFrame()
 
-  if frame.state.mouseElement == frame.Menu || frame.state.mouseElement == nil {
+  if frame.isMenu(frame.state.mouseElement) || frame.state.mouseElement == nil {
     // eg. on edge of menu
     return
   }
 
   frame.state.lastDown = frame.state.mouseElement
+  frame.state.lastClicked = frame.state.mouseElement
 
   frame.state.lastDownX = int(event.X)
   frame.state.lastDownY = int(event.Y)
@@ -255,7 +301,7 @@ func (app *App) onMouseDown(event *sdl.MouseButtonEvent) {
     app.triggerHitEvent("rightmousedown", NewMouseEvent(int(event.X), int(event.Y)))
   }
 
-  if !hasAncestor(frame.state.mouseElement, frame.Menu) {
+  if !frame.inMenu(frame.state.mouseElement) {
     newFocusable := findFocusable(frame.state.mouseElement)
 
     blurEvt := NewMouseEvent(int(event.X), int(event.Y))
@@ -372,6 +418,17 @@ func (app *App) onTab(event *sdl.KeyboardEvent) {
 
   shift := event.Keysym.Mod & sdl.KMOD_SHIFT > 0
 
+  // the focus element can handle tab itself (e.g. moving to the next table cell while editing)
+  if elementNotNil(frame.state.focusElement) && hasEvent(frame.state.focusElement, "tab") {
+    evt := NewKeyboardEvent("tab", false, shift, false)
+
+    TriggerEvent(frame.state.focusElement, "tab", evt)
+
+    if evt.stopPropagation {
+      return
+    }
+  }
+
   if elementNotNil(frame.state.focusElement) && !frame.FocusRect.IsOwnedBy(frame.state.focusElement) {
     newFocusable = frame.state.focusElement
   } else {
@@ -411,6 +468,66 @@ func (app *App) onKeyPress(event *sdl.KeyboardEvent) {
   }
 }
 
+// the Tabbed containing the focus element, or else the last clicked element
+func (app *App) currentTabbed() *Tabbed {
+  frame := app.ActiveFrame()
+
+  if tabbed := findTabbed(frame.state.focusElement); tabbed != nil {
+    return tabbed
+  }
+
+  return findTabbed(frame.state.lastClicked)
+}
+
+// behaves like a plain tab outside a Tabbed
+func (app *App) onCtrlTab(event *sdl.KeyboardEvent) {
+  tabbed := app.currentTabbed()
+  if tabbed == nil || tabbed.Deleted() || !tabbed.Visible() {
+    app.onTab(event)
+    return
+  }
+
+  app.hideMenuIfVisible()
+
+  if event.Keysym.Mod & sdl.KMOD_SHIFT > 0 {
+    tabbed.cycle(-1)
+  } else {
+    tabbed.cycle(1)
+  }
+}
+
+// only closes closeable tabs, after the "tabclose" event
+// if there is nothing to close the key is passed on to the focus element
+func (app *App) onCtrlW(event *sdl.KeyboardEvent) {
+  tabbed := app.currentTabbed()
+  if tabbed == nil || tabbed.Deleted() || !tabbed.Visible() || tabbed.active < 0 || !tabbed.lips[tabbed.active].closeable {
+    app.onKeyPress(event)
+    return
+  }
+
+  app.hideMenuIfVisible()
+
+  tabbed.lips[tabbed.active].closeTab()
+}
+
+// pressing and releasing alt by itself toggles the menu bar
+func (app *App) onAlt(event *sdl.KeyboardEvent) {
+  frame := app.ActiveFrame()
+
+  if event.State == sdl.PRESSED {
+    if event.Repeat == 0 {
+      frame.state.altAlone = true
+    }
+  } else if frame.state.altAlone {
+    frame.state.altAlone = false
+
+    bar := frame.menuBar
+    if bar != nil && !bar.Deleted() && bar.Visible() {
+      bar.toggle()
+    }
+  }
+}
+
 func (app *App) onShowOrResize() {
   app.syncWindowSize()
 
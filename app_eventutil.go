@@ -34,6 +34,13 @@ func TriggerEvent(e Element, name string, evt *Event) {
   }
 }
 
+// moves the keyboard focus to e
+func Focus(e Element) {
+  app := getApp()
+
+  app.changeFocusElement(e, NewMouseEvent(-1, -1), NewMouseEvent(-1, -1))
+}
+
 func (app *App) triggerHitEvent(name string, evt *Event) {
   frame := app.ActiveFrame()
 
@@ -127,6 +134,8 @@ func (app *App) updateMouseElement(x, y int, dx, dy int) {
     }
   }
 
+  frame.debugOverlay.setHover(frame.state.mouseElement)
+
   mouseMove := dx != 0 || dy != 0
   // mousemove event must be triggered before cursor is updated, as mousemove might change cursor
   if mouseMove {
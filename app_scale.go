@@ -0,0 +1,152 @@
+package glui
+
+import (
+  "math"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+  REFERENCE_DPI = 96.0 // dpi at which the scale is 1.0
+  MIN_SCALE     = 0.5
+  MAX_SCALE     = 4.0
+  ZOOM_STEP     = 0.25 // scale increment per Ctrl+plus/minus
+)
+
+// layout is done in logical pixels, the scale converts logical pixels into physical pixels
+
+// scale is rounded to quarters so that borders stay sharp at the common scales
+func roundScale(s float64) float64 {
+  s = math.Round(s*4.0)/4.0
+
+  if s < MIN_SCALE {
+    s = MIN_SCALE
+  } else if s > MAX_SCALE {
+    s = MAX_SCALE
+  }
+
+  return s
+}
+
+func (app *App) detectScale() float64 {
+  if app.window == nil {
+    return 1.0
+  }
+
+  iDisplay, err := app.window.GetDisplayIndex()
+  if err != nil {
+    return 1.0
+  }
+
+  _, hdpi, _, err := sdl.GetDisplayDPI(iDisplay)
+  if err != nil || hdpi <= 0.0 {
+    return 1.0
+  }
+
+  return roundScale(float64(hdpi)/REFERENCE_DPI)
+}
+
+// scale without zoom
+func (app *App) baseScale() float64 {
+  if app.scaleOverride > 0.0 {
+    return app.scaleOverride
+  } else {
+    return app.detectScale()
+  }
+}
+
+func (app *App) syncScale() {
+  scale := roundScale(app.baseScale()*app.zoom)
+
+  app.scale = scale
+
+  app.skinMap.setScale(scale)
+
+  if app.window != nil {
+    // zoom doesn't affect the minimum size
+    s := app.baseScale()
+    app.window.SetMinimumSize(int32(1024*s), int32(764*s))
+
+    app.syncWindowSize() // relayouts all frames
+  }
+}
+
+func (app *App) setZoom(zoom float64) {
+  // also stops at MIN_SCALE and MAX_SCALE
+  if roundScale(zoom*app.baseScale()) == app.scale {
+    return
+  }
+
+  app.zoom = zoom
+
+  app.syncScale()
+}
+
+// physical pixels to logical pixels
+func (app *App) toLogical(x int) int {
+  return int(math.Floor(float64(x)/app.scale))
+}
+
+// logical pixels to physical pixels
+func (app *App) toPhysical(x int) int {
+  return int(math.Round(float64(x)*app.scale))
+}
+
+// mouse events are converted in-place before they are handled
+func (app *App) scaleMouseEvent(event_ sdl.Event) {
+  if app.scale == 1.0 {
+    return
+  }
+
+  switch event := event_.(type) {
+  case *sdl.MouseMotionEvent:
+    x, y := app.toLogical(int(event.X)), app.toLogical(int(event.Y))
+
+    // relative motion is derived from the logical positions, so small movements aren't lost
+    event.XRel = int32(x - app.toLogical(int(event.X - event.XRel)))
+    event.YRel = int32(y - app.toLogical(int(event.Y - event.YRel)))
+    event.X, event.Y = int32(x), int32(y)
+  case *sdl.MouseButtonEvent:
+    event.X, event.Y = int32(app.toLogical(int(event.X))), int32(app.toLogical(int(event.Y)))
+  }
+}
+
+// 0.0 reverts to the scale detected from the display dpi
+// can be called before Run()
+func SetScale(s float64) {
+  app := getApp()
+
+  if s <= 0.0 {
+    app.scaleOverride = 0.0
+  } else {
+    app.scaleOverride = roundScale(s)
+  }
+
+  app.syncScale()
+}
+
+// includes zoom
+func Scale() float64 {
+  app := getApp()
+
+  return app.scale
+}
+
+func ZoomIn() {
+  app := getApp()
+
+  // zoom in quarter steps of the resulting scale
+  app.setZoom((app.scale + ZOOM_STEP)/app.baseScale())
+}
+
+func ZoomOut() {
+  app := getApp()
+
+  app.setZoom((app.scale - ZOOM_STEP)/app.baseScale())
+}
+
+func ResetZoom() {
+  app := getApp()
+
+  app.setZoom(1.0)
+}
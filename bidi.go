@@ -0,0 +1,239 @@
+package glui
+
+import (
+  "unicode"
+)
+
+// simplified version of the unicode bidirectional algorithm:
+//  * no explicit embeddings or isolates
+//  * arabic numbers are treated like european numbers
+//  * no shaping of arabic letters
+
+type bidiClass int
+
+const (
+  bidiL  bidiClass = iota // strong left-to-right
+  bidiR                   // strong right-to-left
+  bidiEN                  // number
+  bidiN                   // neutral (whitespace and punctuation)
+)
+
+func getBidiClass(r rune) bidiClass {
+  switch {
+  case unicode.IsDigit(r):
+    return bidiEN
+  case unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko):
+    return bidiR
+  case unicode.IsLetter(r):
+    return bidiL
+  default:
+    return bidiN
+  }
+}
+
+// direction of first strong character, falls back to rtl if there isn't any
+func bidiBaseLevel(runes []rune, rtl bool) int {
+  for _, r := range runes {
+    switch getBidiClass(r) {
+    case bidiL:
+      return 0
+    case bidiR:
+      return 1
+    }
+  }
+
+  if rtl {
+    return 1
+  } else {
+    return 0
+  }
+}
+
+// even levels are ltr, odd levels are rtl
+func bidiLevels(runes []rune, rtl bool) []int {
+  n := len(runes)
+
+  base := bidiBaseLevel(runes, rtl)
+
+  baseClass := bidiL
+  if base == 1 {
+    baseClass = bidiR
+  }
+
+  classes := make([]bidiClass, n)
+  for i, r := range runes {
+    classes[i] = getBidiClass(r)
+  }
+
+  // numbers preceded by ltr text are ltr text themselves
+  prevStrong := baseClass
+  for i, c := range classes {
+    if c == bidiL || c == bidiR {
+      prevStrong = c
+    } else if c == bidiEN && prevStrong == bidiL {
+      classes[i] = bidiL
+    }
+  }
+
+  // neutrals get the direction of the surrounding text if both sides agree, otherwise the base direction
+  // numbers count as rtl here
+  strongDir := func(c bidiClass) bidiClass {
+    if c == bidiEN {
+      return bidiR
+    } else {
+      return c
+    }
+  }
+
+  for i := 0; i < n; {
+    if classes[i] != bidiN {
+      i++
+      continue
+    }
+
+    j := i
+    for ; j < n && classes[j] == bidiN; {
+      j++
+    }
+
+    prev := baseClass
+    if i > 0 {
+      prev = strongDir(classes[i-1])
+    }
+
+    next := baseClass
+    if j < n {
+      next = strongDir(classes[j])
+    }
+
+    dir := baseClass
+    if prev == next {
+      dir = prev
+    }
+
+    for k := i; k < j; k++ {
+      classes[k] = dir
+    }
+
+    i = j
+  }
+
+  levels := make([]int, n)
+  for i, c := range classes {
+    switch {
+    case c == bidiR && base == 0:
+      levels[i] = 1
+    case c == bidiEN:
+      levels[i] = 2
+    case c == bidiL && base == 1:
+      levels[i] = 2
+    default:
+      levels[i] = base
+    }
+  }
+
+  // trailing whitespace gets the base level
+  for i := n - 1; i >= 0 && isWhitespace(runes[i]); i-- {
+    levels[i] = base
+  }
+
+  return levels
+}
+
+// returns the logical indices in visual order (left to right)
+func bidiVisualOrder(levels []int) []int {
+  n := len(levels)
+
+  order := make([]int, n)
+  lv := make([]int, n)
+
+  maxLevel := 0
+  minOddLevel := -1
+  for i, l := range levels {
+    order[i] = i
+    lv[i] = l
+
+    if l > maxLevel {
+      maxLevel = l
+    }
+
+    if l%2 == 1 && (minOddLevel < 0 || l < minOddLevel) {
+      minOddLevel = l
+    }
+  }
+
+  if minOddLevel < 0 {
+    // only ltr text
+    return order
+  }
+
+  // reverse every sequence at that level or higher, from the highest level down to the lowest odd level
+  for level := maxLevel; level >= minOddLevel; level-- {
+    for i := 0; i < n; {
+      if lv[i] < level {
+        i++
+        continue
+      }
+
+      j := i
+      for ; j < n && lv[j] >= level; {
+        j++
+      }
+
+      for a, b := i, j - 1; a < b; a, b = a + 1, b - 1 {
+        order[a], order[b] = order[b], order[a]
+        lv[a], lv[b] = lv[b], lv[a]
+      }
+
+      i = j
+    }
+  }
+
+  return order
+}
+
+// brackets are mirrored inside rtl runs
+func bidiMirror(r rune) rune {
+  switch r {
+  case '(':
+    return ')'
+  case ')':
+    return '('
+  case '[':
+    return ']'
+  case ']':
+    return '['
+  case '{':
+    return '}'
+  case '}':
+    return '{'
+  case '<':
+    return '>'
+  case '>':
+    return '<'
+  case '«':
+    return '»'
+  case '»':
+    return '«'
+  default:
+    return r
+  }
+}
+
+// runes in visual order
+func bidiReorder(runes []rune, rtl bool) []rune {
+  levels := bidiLevels(runes, rtl)
+
+  order := bidiVisualOrder(levels)
+
+  res := make([]rune, len(runes))
+  for v, i := range order {
+    if levels[i]%2 == 1 {
+      res[v] = bidiMirror(runes[i])
+    } else {
+      res[v] = runes[i]
+    }
+  }
+
+  return res
+}
@@ -15,6 +15,7 @@ func (e *Body) A(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -42,13 +43,13 @@ func (e *Body) Padding(p ...int) *Body {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Body) Spacing(s int) *Body {
   e.spacing = s
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -0,0 +1,107 @@
+package glui
+
+import (
+  "strconv"
+  "strings"
+)
+
+//go:generate ./gen_element BoolColumn "appendChild CalcDepth"
+
+// cells are checkboxes, which are toggled by clicking them (if the column is editable)
+type BoolColumn struct {
+  BasicColumn
+}
+
+func NewBoolColumn(caption string) *BoolColumn {
+  e_ := NewBasicColumn(CENTER)
+
+  e := &BoolColumn{
+    *e_,
+  }
+
+  e.self = e
+  e.head, e.caption, e.arrow, e.prio = newHeadButton(caption)
+  e.head.OnClick(e.onClickSort)
+
+  return e
+}
+
+// values can be bools or strings accepted by strconv.ParseBool
+func toBool(x interface{}) bool {
+  switch b := x.(type) {
+  case bool:
+    return b
+  case string:
+    res, err := strconv.ParseBool(strings.TrimSpace(b))
+    if err != nil {
+      panic(err)
+    }
+
+    return res
+  default:
+    panic("value is not a bool")
+  }
+}
+
+func (e *BoolColumn) Format(x interface{}) string {
+  if isEmptyCell(x) {
+    return ""
+  }
+
+  return strconv.FormatBool(toBool(x))
+}
+
+// false before true
+func (e *BoolColumn) Less(a, b interface{}) bool {
+  if less, ok := lessEmptyCell(a, b); ok {
+    return less
+  }
+
+  return !toBool(a) && toBool(b)
+}
+
+func (e *BoolColumn) Parse(s string) (interface{}, error) {
+  return strconv.ParseBool(strings.TrimSpace(s))
+}
+
+// the cell isn't interactive itself (not focusable), the click is handled here and written to the model
+func (e *BoolColumn) newCell() Element {
+  cb := newCheckbox(false)
+
+  cb.On("click", func(evt *Event) {
+    e.toggle(cb)
+  })
+
+  // the second of two fast clicks is a doubleclick (because the table listens for it), which must also toggle
+  //  instead of activating the row
+  cb.On("doubleclick", func(evt *Event) {
+    if e.table.editable(e) {
+      e.toggle(cb)
+
+      evt.StopPropagation()
+    }
+  })
+
+  return cb
+}
+
+func (e *BoolColumn) setCell(cell Element, x interface{}, selected bool) {
+  cell.(*Checkbox).SetValue(toBool(x))
+}
+
+func (e *BoolColumn) toggle(cb *Checkbox) {
+  if !e.table.editable(e) {
+    return
+  }
+
+  for j, cell := range e.cells {
+    if cell == cb {
+      e.table.setCell(e.first + j, e, !cb.Value())
+      return
+    }
+  }
+}
+
+func (e *BoolColumn) onClickSort() {
+  e.table.clickSort(e)
+}
@@ -5,6 +5,7 @@ func (e *Button) A(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -28,7 +29,7 @@ func (e *Button) On(name string, fn EventListener) *Button {
 func (e *Button) Size(w, h int) *Button {
   e.width = w
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -49,19 +50,19 @@ func (e *Button) Padding(p ...int) *Button {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Button) H(h int) *Button {
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Button) W(w int) *Button {
   e.width = w
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
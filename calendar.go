@@ -0,0 +1,302 @@
+package glui
+
+import (
+  "strconv"
+  "time"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element calendar "CalcDepth appendChild"
+//go:generate ./gen_element calendarDay "CalcDepth appendChild On"
+
+const (
+  CALENDAR_CELL_WIDTH  = 32
+  CALENDAR_CELL_HEIGHT = 24
+  CALENDAR_PADDING     = 4
+  CALENDAR_TODAY_WIDTH = 80
+)
+
+var (
+  CALENDAR_VALUE_COLOR    = sdl.Color{0xd0, 0xe0, 0xff, 0xff} // background of the current value
+  CALENDAR_OTHER_COLOR    = sdl.Color{0x90, 0x90, 0x90, 0xff} // days of the previous and next month
+  CALENDAR_DISABLED_COLOR = sdl.Color{0xc8, 0xc8, 0xc8, 0xff} // days outside the range of the picker
+)
+
+// month grid shown in the frame menu by a DatePicker
+// children: prev button, caption, next button, 7 weekday names, 6x7 days and the today button
+type calendar struct {
+  ElementData
+
+  picker *DatePicker
+  month  time.Time // first day of the shown month
+  cursor time.Time // day selected with the keyboard or the mouse
+
+  prev     *Button
+  caption  *Text
+  next     *Button
+  weekdays []*Text
+  days     []*calendarDay
+  today    *Button
+}
+
+func newCalendar(picker *DatePicker, cursor time.Time) *calendar {
+  // transposed up arrow points left, the cells are mirrored for RTL
+  prevGlyph, nextGlyph := "arrow-up-drop", "arrow-down-drop"
+  if isRTL() {
+    prevGlyph, nextGlyph = nextGlyph, prevGlyph
+  }
+
+  e := &calendar{
+    NewElementData(0, 0),
+    picker,
+    time.Time{},
+    time.Time{},
+    NewIconButton(prevGlyph, 10, VER),
+    NewSans("", 10),
+    NewIconButton(nextGlyph, 10, VER),
+    make([]*Text, 7),
+    make([]*calendarDay, 6*7),
+    NewCaptionButton("Today"),
+  }
+
+  e.appendChild(e.prev, e.caption, e.next)
+
+  for i := range e.weekdays {
+    e.weekdays[i] = NewSans("", 10)
+    e.appendChild(e.weekdays[i])
+  }
+
+  for i := range e.days {
+    e.days[i] = newCalendarDay(e)
+    e.appendChild(e.days[i])
+  }
+
+  e.appendChild(e.today)
+
+  // the input of the picker keeps the focus while the calendar is shown
+  for _, btn := range []*Button{e.prev, e.next, e.today} {
+    delete(btn.evtListeners, "focus")
+  }
+
+  e.prev.OnClick(func() {
+    e.setCursor(addMonths(e.cursor, -1))
+  })
+
+  e.next.OnClick(func() {
+    e.setCursor(addMonths(e.cursor, 1))
+  })
+
+  e.today.OnClick(func() {
+    if picker.inRange(today()) {
+      picker.choose(today())
+    } else {
+      e.setCursor(today())
+    }
+  })
+
+  e.setCursor(toDate(cursor))
+
+  return e
+}
+
+func (e *calendar) size() (int, int) {
+  w := 7*CALENDAR_CELL_WIDTH + 2*CALENDAR_PADDING
+
+  // header, weekday names, days, spacing and today button
+  h := (1 + 1 + 6)*CALENDAR_CELL_HEIGHT + CALENDAR_PADDING + CALENDAR_CELL_HEIGHT + 2*CALENDAR_PADDING
+
+  return w, h
+}
+
+func (e *calendar) setCursor(t time.Time) {
+  e.cursor = t
+
+  month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+  if !month.Equal(e.month) {
+    e.month = month
+  }
+
+  e.sync()
+}
+
+// first day shown in the grid
+func (e *calendar) gridStart() time.Time {
+  offset := (int(e.month.Weekday()) - int(e.picker.firstWeekday) + 7)%7
+
+  return e.month.AddDate(0, 0, -offset)
+}
+
+func (e *calendar) sync() {
+  e.caption.SetContent(e.month.Month().String() + " " + strconv.Itoa(e.month.Year()))
+
+  for i, text := range e.weekdays {
+    text.SetContent(time.Weekday((int(e.picker.firstWeekday) + i)%7).String()[0:2])
+  }
+
+  start := e.gridStart()
+
+  for i, day := range e.days {
+    day.setDate(start.AddDate(0, 0, i))
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *calendar) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.size()
+
+  p := CALENDAR_PADDING
+  cw := CALENDAR_CELL_WIDTH
+  ch := CALENDAR_CELL_HEIGHT
+
+  // the cells are mirrored for RTL
+  cellX := func(col int) int {
+    if isRTL() {
+      col = 6 - col
+    }
+
+    return p + col*cw
+  }
+
+  // header
+  e.prev.width, e.prev.height = cw, ch
+  calcPos(e.prev, cw, ch, maxZIndex)
+
+  e.next.width, e.next.height = cw, ch
+  calcPos(e.next, cw, ch, maxZIndex)
+
+  e.prev.Translate(cellX(0), p)
+  e.next.Translate(cellX(6), p)
+
+  tw, th := calcPos(e.caption, w, ch, maxZIndex)
+  e.caption.Translate((w - tw)/2, p + (ch - th)/2)
+
+  // weekday names
+  y := p + ch
+  for i, text := range e.weekdays {
+    tw, th := calcPos(text, cw, ch, maxZIndex)
+    text.Translate(cellX(i) + (cw - tw)/2, y + (ch - th)/2)
+  }
+
+  // days
+  y += ch
+  for i, day := range e.days {
+    calcPos(day, cw, ch, maxZIndex)
+    day.Translate(cellX(i%7), y + (i/7)*ch)
+  }
+
+  // today button
+  y += 6*ch + p
+  e.today.width, e.today.height = CALENDAR_TODAY_WIDTH, ch
+  calcPos(e.today, CALENDAR_TODAY_WIDTH, ch, maxZIndex)
+  e.today.Translate((w - CALENDAR_TODAY_WIDTH)/2, y)
+
+  return e.InitRect(w, h)
+}
+
+// a single quad as background, with the day of the month as child
+type calendarDay struct {
+  ElementData
+
+  cal  *calendar
+  text *Text
+  date time.Time
+}
+
+func newCalendarDay(cal *calendar) *calendarDay {
+  e := &calendarDay{
+    NewElementData(2, 0),
+    cal,
+    NewSans("", 10),
+    time.Time{},
+  }
+
+  e.appendChild(e.text)
+
+  e.On("mouseenter", e.onMouseEnter)
+  e.On("click",      e.onMouseClick)
+
+  return e
+}
+
+func (e *calendarDay) enabled_() bool {
+  return e.cal.picker.inRange(e.date)
+}
+
+func (e *calendarDay) setDate(t time.Time) {
+  e.date = t
+
+  e.text.SetContent(strconv.Itoa(t.Day()))
+
+  e.setTypesAndColor()
+}
+
+func (e *calendarDay) setTypesAndColor() {
+  bg := e.Root.P1.Skin.BGColor()
+  fg := BLACK
+
+  if e.date.Equal(e.cal.cursor) && e.enabled_() {
+    bg = e.Root.P1.Skin.SelColor()
+    fg = WHITE
+  } else {
+    if e.date.Equal(e.cal.picker.value) {
+      bg = CALENDAR_VALUE_COLOR
+    }
+
+    if !e.enabled_() {
+      fg = CALENDAR_DISABLED_COLOR
+    } else if e.date.Month() != e.cal.month.Month() {
+      fg = CALENDAR_OTHER_COLOR
+    } else if e.date.Equal(today()) {
+      fg = e.Root.P1.Skin.SelColor()
+    }
+  }
+
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri, bg)
+  }
+
+  e.text.SetColor(fg)
+}
+
+func (e *calendarDay) onMouseEnter(evt *Event) {
+  if e.enabled_() && !e.date.Equal(e.cal.cursor) {
+    // don't switch months while hovering
+    e.cal.cursor = e.date
+
+    for _, day := range e.cal.days {
+      day.setTypesAndColor()
+    }
+  }
+}
+
+func (e *calendarDay) onMouseClick(evt *Event) {
+  e.cal.picker.choose(e.date)
+}
+
+func (e *calendarDay) Cursor(x, y int) int {
+  if e.enabled_() {
+    return sdl.SYSTEM_CURSOR_HAND
+  } else {
+    return -1
+  }
+}
+
+func (e *calendarDay) Show() {
+  e.setTypesAndColor()
+
+  e.ElementData.Show()
+}
+
+func (e *calendarDay) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := CALENDAR_CELL_WIDTH, CALENDAR_CELL_HEIGHT
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, h}, e.Z(maxZIndex))
+
+  tw, th := calcPos(e.text, w, h, maxZIndex)
+  e.text.Translate((w - tw)/2, (h - th)/2)
+
+  return e.InitRect(w, h)
+}
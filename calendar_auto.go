@@ -0,0 +1,17 @@
+package glui
+func (e *calendar) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *calendar) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
@@ -0,0 +1,27 @@
+package glui
+func (e *calendarDay) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *calendarDay) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *calendarDay) On(name string, fn EventListener) *calendarDay {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
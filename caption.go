@@ -62,10 +62,10 @@ func (e *Caption) Enable() {
 }
 
 func (e *Caption) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
-  w, h := e.main.CalcPos(maxWidth, maxHeight, maxZIndex)
+  w, h := calcPos(e.main, maxWidth, maxHeight, maxZIndex)
 
   if !e.enabled {
-    e.back.CalcPos(maxWidth, maxHeight, maxZIndex)
+    calcPos(e.back, maxWidth, maxHeight, maxZIndex)
 
     e.back.Translate(1, 1)
   }
@@ -11,6 +11,7 @@ func (e *Caption) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
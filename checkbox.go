@@ -9,6 +9,11 @@ type Checkbox struct {
 }
 
 func NewCheckbox() *Checkbox {
+  return newCheckbox(true)
+}
+
+// a non-interactive checkbox isn't focusable and doesn't toggle itself (e.g. for table cells)
+func newCheckbox(interactive bool) *Checkbox {
   e := &Checkbox{
     NewElementData(9*2, 0),
     false,
@@ -19,6 +24,10 @@ func NewCheckbox() *Checkbox {
 
   e.setTypesAndTCoords()
 
+  if !interactive {
+    return e
+  }
+
   e.On("keypress", e.onKeyPress)
   e.On("focus", e.onFocus)
   e.On("blur", e.onBlur)
@@ -115,3 +124,11 @@ func (e *Checkbox) Cursor(x, y int) int {
 func (e *Checkbox) Value() bool {
   return e.value
 }
+
+func (e *Checkbox) SetValue(b bool) {
+  if b != e.value {
+    e.value = b
+
+    e.setTypesAndTCoords()
+  }
+}
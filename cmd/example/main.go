@@ -114,11 +114,11 @@ func main() {
 
   menuItems:= []glui.MenuItemConfig {
     glui.MenuItemConfig{
-      "Close",
-      func(){
+      Caption: "Close",
+      Callback: func(){
         glui.Quit()
       },
-      120,
+      Width: 120,
     },
   }
 
@@ -0,0 +1,18 @@
+package glui
+func (e *colorHueBar) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *colorHueBar) On(name string, fn EventListener) *colorHueBar {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
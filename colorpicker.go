@@ -0,0 +1,364 @@
+package glui
+
+import (
+  "errors"
+  "fmt"
+  "math"
+  "strconv"
+  "strings"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element ColorPicker "CalcDepth On Size"
+
+const (
+  COLORPICKER_SWATCH_INSET = 4
+  COLORPICKER_MAX_RECENT   = 8
+)
+
+// shared by all the ColorPickers, most recent first
+var recentColors []sdl.Color
+
+// button that shows the chosen color as a swatch, and opens a popup in the frame menu
+// first 9 quads are styled like a button, the last quad is the swatch
+type ColorPicker struct {
+  ElementData
+
+  value sdl.Color
+
+  // kept separately, so the hue isn't lost for grays
+  hue float64 // between 0.0 and 360.0
+  sat float64 // between 0.0 and 1.0
+  val float64 // between 0.0 and 1.0
+
+  popup   *colorPopup // nil if the popup isn't shown
+  initial sdl.Color   // value when the popup was opened
+
+  onChange func(c sdl.Color)
+}
+
+func NewColorPicker(c sdl.Color) *ColorPicker {
+  e := &ColorPicker{
+    NewElementData(10*2, 0),
+    c,
+    0.0, 0.0, 0.0,
+    nil,
+    c,
+    nil,
+  }
+
+  e.width, e.height = 60, 30
+
+  e.hue, e.sat, e.val = rgbToHSV(c)
+
+  e.Show()
+
+  e.On("mousedown", e.onMouseDown)
+  e.On("mousebuttonoutsidemenu", e.onMouseButtonOutsideMenu)
+  e.On("focus", e.onFocus)
+  e.On("blur", e.onBlur)
+  e.On("keypress", e.onKeyPress)
+
+  return e
+}
+
+func (e *ColorPicker) OnChange(fn func(c sdl.Color)) *ColorPicker {
+  e.onChange = fn
+
+  return e
+}
+
+func (e *ColorPicker) Value() sdl.Color {
+  return e.value
+}
+
+func (e *ColorPicker) SetValue(c sdl.Color) {
+  e.hue, e.sat, e.val = rgbToHSV(c)
+
+  e.setValue(c)
+}
+
+// used by the popup, so the hue and saturation survive a zero value
+func (e *ColorPicker) setHSV(h, s, v float64) {
+  e.hue, e.sat, e.val = h, s, v
+
+  c := hsvToRGB(h, s, v)
+  c.A = e.value.A
+
+  e.setValue(c)
+}
+
+func (e *ColorPicker) setAlpha(a uint8) {
+  c := e.value
+  c.A = a
+
+  e.setValue(c)
+}
+
+func (e *ColorPicker) setValue(c sdl.Color) {
+  changed := c != e.value
+
+  e.value = c
+
+  e.setTypesAndColor()
+
+  if e.popupVisible() {
+    e.popup.sync()
+  }
+
+  if changed && e.onChange != nil {
+    e.onChange(c)
+  }
+}
+
+func (e *ColorPicker) setTypesAndColor() {
+  tri0 := e.p1Tris[18]
+  tri1 := e.p1Tris[19]
+
+  e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+  e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+
+  e.Root.P1.SetColorConst(tri0, e.value)
+  e.Root.P1.SetColorConst(tri1, e.value)
+}
+
+func (e *ColorPicker) Cursor(x, y int) int {
+  if e.enabled {
+    return sdl.SYSTEM_CURSOR_HAND
+  } else {
+    return -1
+  }
+}
+
+func (e *ColorPicker) popupVisible() bool {
+  return e.popup != nil && e.Root.Menu.IsOwnedBy(e)
+}
+
+func (e *ColorPicker) togglePopup() {
+  if e.popupVisible() {
+    e.hidePopup()
+  } else {
+    e.showPopup()
+  }
+}
+
+func (e *ColorPicker) showPopup() {
+  e.initial = e.value
+
+  menu := e.Root.Menu
+
+  menu.ClearChildren()
+
+  e.popup = newColorPopup(e)
+
+  menu.A(e.popup)
+
+  w, h := e.popup.size()
+  t := e.Root.P1.Skin.ButtonBorderThickness()
+
+  menu.height = h + 2*t
+
+  menu.ShowAt(e, 0.0, 1.0, w + 2*t)
+}
+
+// the chosen color is added to the recent colors
+func (e *ColorPicker) hidePopup() {
+  if e.popup == nil {
+    return
+  }
+
+  // the hex input of the popup might have the focus
+  refocus := e.popup.hex.focused()
+
+  if e.popupVisible() {
+    e.Root.Menu.Hide()
+  }
+
+  e.popup = nil
+
+  if refocus {
+    Focus(e)
+  }
+
+  if e.value != e.initial {
+    addRecentColor(e.value)
+  }
+}
+
+func (e *ColorPicker) onMouseDown(evt *Event) {
+  if e.enabled {
+    e.togglePopup()
+  }
+}
+
+func (e *ColorPicker) onMouseButtonOutsideMenu(evt *Event) {
+  if !e.IsHit(evt.X, evt.Y) {
+    e.hidePopup()
+  }
+}
+
+func (e *ColorPicker) onFocus(evt *Event) {
+  if evt.IsKeyboardEvent() {
+    e.Root.FocusRect.Show(e)
+  }
+}
+
+func (e *ColorPicker) onBlur(evt *Event) {
+  e.Root.FocusRect.Hide()
+}
+
+func (e *ColorPicker) onKeyPress(evt *Event) {
+  if evt.IsReturnOrSpace() {
+    e.togglePopup()
+  } else if evt.Key == "escape" {
+    e.hidePopup()
+  }
+}
+
+func (e *ColorPicker) Show() {
+  e.SetButtonStyle()
+  e.setTypesAndColor()
+
+  e.ElementData.Show()
+}
+
+func (e *ColorPicker) Hide() {
+  e.hidePopup()
+
+  e.ElementData.Hide()
+}
+
+func (e *ColorPicker) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  if w > maxWidth {
+    w = maxWidth
+  }
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  t := e.Root.P1.Skin.ButtonBorderThickness()
+
+  e.SetBorderedElementPos(w, h, t, maxZIndex)
+
+  // swatch in front of the button background
+  d := t + COLORPICKER_SWATCH_INSET
+  z := e.Z(maxZIndex) - 0.5/float32(maxZIndex)
+
+  e.Root.P1.SetQuadPos(e.p1Tris[18], e.p1Tris[19], Rect{d, d, w - 2*d, h - 2*d}, z)
+
+  return e.InitRect(w, h)
+}
+
+func addRecentColor(c sdl.Color) {
+  colors := []sdl.Color{c}
+
+  for _, other := range recentColors {
+    if other != c && len(colors) < COLORPICKER_MAX_RECENT {
+      colors = append(colors, other)
+    }
+  }
+
+  recentColors = colors
+}
+
+// h between 0.0 and 360.0, s and v between 0.0 and 1.0, the alpha is opaque
+func hsvToRGB(h, s, v float64) sdl.Color {
+  h = math.Mod(h, 360.0)
+  if h < 0.0 {
+    h += 360.0
+  }
+
+  c := v*s
+  x := c*(1.0 - math.Abs(math.Mod(h/60.0, 2.0) - 1.0))
+  m := v - c
+
+  var r, g, b float64
+
+  switch int(h/60.0) {
+  case 0:
+    r, g, b = c, x, 0.0
+  case 1:
+    r, g, b = x, c, 0.0
+  case 2:
+    r, g, b = 0.0, c, x
+  case 3:
+    r, g, b = 0.0, x, c
+  case 4:
+    r, g, b = x, 0.0, c
+  default:
+    r, g, b = c, 0.0, x
+  }
+
+  toByte := func(f float64) uint8 {
+    return uint8(math.Round((f + m)*255.0))
+  }
+
+  return sdl.Color{toByte(r), toByte(g), toByte(b), 0xff}
+}
+
+// the alpha is ignored
+func rgbToHSV(c sdl.Color) (float64, float64, float64) {
+  r := float64(c.R)/255.0
+  g := float64(c.G)/255.0
+  b := float64(c.B)/255.0
+
+  max := math.Max(r, math.Max(g, b))
+  min := math.Min(r, math.Min(g, b))
+  d := max - min
+
+  h := 0.0
+  if d > 0.0 {
+    switch max {
+    case r:
+      h = 60.0*math.Mod((g - b)/d, 6.0)
+    case g:
+      h = 60.0*((b - r)/d + 2.0)
+    default:
+      h = 60.0*((r - g)/d + 4.0)
+    }
+
+    if h < 0.0 {
+      h += 360.0
+    }
+  }
+
+  s := 0.0
+  if max > 0.0 {
+    s = d/max
+  }
+
+  return h, s, max
+}
+
+// #rrggbb, or #rrggbbaa if not opaque
+func formatHexColor(c sdl.Color) string {
+  if c.A == 0xff {
+    return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+  } else {
+    return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+  }
+}
+
+// #rrggbb or #rrggbbaa, the # is optional
+func parseHexColor(s string) (sdl.Color, error) {
+  s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+
+  if len(s) != 6 && len(s) != 8 {
+    return sdl.Color{}, errors.New("expected 6 or 8 hex digits")
+  }
+
+  if len(s) == 6 {
+    s += "ff"
+  }
+
+  v, err := strconv.ParseUint(s, 16, 32)
+  if err != nil {
+    return sdl.Color{}, err
+  }
+
+  return sdl.Color{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
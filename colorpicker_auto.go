@@ -0,0 +1,25 @@
+package glui
+func (e *ColorPicker) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *ColorPicker) On(name string, fn EventListener) *ColorPicker {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
+func (e *ColorPicker) Size(w, h int) *ColorPicker {
+  e.width = w
+  e.height = h
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
@@ -0,0 +1,454 @@
+package glui
+
+import (
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element colorPopup "CalcDepth appendChild On"
+//go:generate ./gen_element colorSVArea "CalcDepth On"
+//go:generate ./gen_element colorHueBar "CalcDepth On"
+//go:generate ./gen_element colorSwatch "CalcDepth On"
+
+const (
+  COLORPOPUP_PADDING     = 6
+  COLORPOPUP_AREA_SIZE   = 150
+  COLORPOPUP_HUE_WIDTH   = 20
+  COLORPOPUP_HEX_WIDTH   = 100
+  COLORPOPUP_HEX_HEIGHT  = 24
+  COLORPOPUP_SWATCH_SIZE = 18
+  COLORPOPUP_MARKER_SIZE = 9
+)
+
+// content of the frame menu shown by a ColorPicker
+// children: saturation/value area, hue bar, alpha slider, hex input and the recent colors
+type colorPopup struct {
+  ElementData
+
+  picker *ColorPicker
+
+  area   *colorSVArea
+  hue    *colorHueBar
+  alpha  *Slider
+  hex    *Input
+  recent []*colorSwatch
+}
+
+func newColorPopup(picker *ColorPicker) *colorPopup {
+  e := &colorPopup{
+    NewElementData(0, 0),
+    picker,
+    nil,
+    nil,
+    NewSlider(HOR),
+    NewInput(),
+    make([]*colorSwatch, len(recentColors)),
+  }
+
+  e.area = newColorSVArea(e)
+  e.hue = newColorHueBar(e)
+
+  e.appendChild(e.area, e.hue, e.alpha, e.hex)
+
+  for i, c := range recentColors {
+    e.recent[i] = newColorSwatch(e, c)
+    e.appendChild(e.recent[i])
+  }
+
+  e.alpha.Range(0.0, 255.0).Step(1.0).OnChange(func(v float64) {
+    picker.setAlpha(uint8(v))
+  })
+
+  // clicking inside the menu doesn't change the focus by itself
+  e.hex.On("mousedown", func(evt *Event) {
+    Focus(e.hex)
+  })
+
+  e.hex.On("blur", e.onBlurHex)
+
+  // bubble up from the hex input
+  e.On("keypress",  e.onKeyPress)
+  e.On("textinput", e.onTextInput)
+
+  e.sync()
+
+  return e
+}
+
+func (e *colorPopup) size() (int, int) {
+  p := COLORPOPUP_PADDING
+
+  w := COLORPOPUP_AREA_SIZE + COLORPOPUP_HUE_WIDTH + 3*p
+
+  h := p + COLORPOPUP_AREA_SIZE + p + SLIDER_KNOB_SIZE + p + COLORPOPUP_HEX_HEIGHT + p
+  if len(e.recent) > 0 {
+    h += COLORPOPUP_SWATCH_SIZE + p
+  }
+
+  return w, h
+}
+
+// called after every change of the picker value
+func (e *colorPopup) sync() {
+  e.alpha.SetValue(float64(e.picker.value.A))
+
+  // don't overwrite the text while it is being typed
+  if !e.hex.focused() {
+    e.hex.SetValue(formatHexColor(e.picker.value))
+    e.hex.SetInvalid(false)
+  }
+
+  e.area.setTypesAndColors()
+  e.Root.ForceElementPosDirty(e)
+}
+
+// keeps the text if it can't be parsed, so it can be corrected
+func (e *colorPopup) commitHex() {
+  s := e.hex.Value()
+  if s == formatHexColor(e.picker.value) {
+    return
+  }
+
+  c, err := parseHexColor(s)
+  if err != nil {
+    e.hex.SetInvalid(true)
+    return
+  }
+
+  e.hex.SetInvalid(false)
+
+  e.picker.SetValue(c)
+}
+
+func (e *colorPopup) onKeyPress(evt *Event) {
+  switch evt.Key {
+  case "return":
+    e.commitHex()
+  case "escape":
+    e.picker.hidePopup()
+  default:
+    _, err := parseHexColor(e.hex.Value())
+    e.hex.SetInvalid(err != nil)
+  }
+}
+
+func (e *colorPopup) onTextInput(evt *Event) {
+  _, err := parseHexColor(e.hex.Value())
+  e.hex.SetInvalid(err != nil)
+}
+
+func (e *colorPopup) onBlurHex(evt *Event) {
+  if e.picker.popup == e {
+    e.commitHex()
+  }
+}
+
+func (e *colorPopup) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.size()
+
+  p := COLORPOPUP_PADDING
+  as := COLORPOPUP_AREA_SIZE
+  hw := COLORPOPUP_HUE_WIDTH
+
+  // the hue bar is on the left for RTL
+  areaX, hueX := p, as + 2*p
+  if isRTL() {
+    areaX, hueX = hw + 2*p, p
+  }
+
+  calcPos(e.area, as, as, maxZIndex)
+  e.area.Translate(areaX, p)
+
+  calcPos(e.hue, hw, as, maxZIndex)
+  e.hue.Translate(hueX, p)
+
+  y := as + 2*p
+
+  e.alpha.width = w - 2*p
+  calcPos(e.alpha, w - 2*p, SLIDER_KNOB_SIZE, maxZIndex)
+  e.alpha.Translate(p, y)
+
+  y += SLIDER_KNOB_SIZE + p
+
+  e.hex.width, e.hex.height = COLORPOPUP_HEX_WIDTH, COLORPOPUP_HEX_HEIGHT
+  calcPos(e.hex, COLORPOPUP_HEX_WIDTH, COLORPOPUP_HEX_HEIGHT, maxZIndex)
+  if isRTL() {
+    e.hex.Translate(w - p - COLORPOPUP_HEX_WIDTH, y)
+  } else {
+    e.hex.Translate(p, y)
+  }
+
+  y += COLORPOPUP_HEX_HEIGHT + p
+
+  sw := COLORPOPUP_SWATCH_SIZE
+  gap := (w - 2*p - COLORPICKER_MAX_RECENT*sw)/(COLORPICKER_MAX_RECENT - 1)
+
+  for i, swatch := range e.recent {
+    x := p + i*(sw + gap)
+    if isRTL() {
+      x = w - x - sw
+    }
+
+    calcPos(swatch, sw, sw, maxZIndex)
+    swatch.Translate(x, y)
+  }
+
+  return e.InitRect(w, h)
+}
+
+// the first quad has the hue as horizontal gradient, the second quad darkens it with a vertical gradient,
+//  the remaining 4 quads are the outline of the marker
+type colorSVArea struct {
+  ElementData
+
+  popup    *colorPopup
+  dragging bool
+}
+
+func newColorSVArea(popup *colorPopup) *colorSVArea {
+  e := &colorSVArea{
+    NewElementData(6*2, 0),
+    popup,
+    false,
+  }
+
+  e.On("mousedown", e.onMouseDown)
+  e.On("mousemove", e.onMouseMove)
+  e.On("mouseup",   e.onMouseUp)
+
+  return e
+}
+
+func (e *colorSVArea) setTypesAndColors() {
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+  }
+
+  picker := e.popup.picker
+
+  e.Root.P1.SetQuadColorLinearHGrad(e.p1Tris[0], e.p1Tris[1], WHITE, hsvToRGB(picker.hue, 1.0, 1.0))
+  e.Root.P1.SetQuadColorLinearVGrad(e.p1Tris[2], e.p1Tris[3], sdl.Color{0x00, 0x00, 0x00, 0x00}, BLACK)
+
+  // the marker must stay visible on dark colors
+  marker := BLACK
+  if picker.val < 0.5 {
+    marker = WHITE
+  }
+
+  for _, tri := range e.p1Tris[4:] {
+    e.Root.P1.SetColorConst(tri, marker)
+  }
+}
+
+func (e *colorSVArea) pick(evt *Event) {
+  picker := e.popup.picker
+
+  s := clampUnit(float64(evt.X - e.rect.X)/float64(e.rect.W))
+  v := 1.0 - clampUnit(float64(evt.Y - e.rect.Y)/float64(e.rect.H))
+
+  picker.setHSV(picker.hue, s, v)
+}
+
+func (e *colorSVArea) onMouseDown(evt *Event) {
+  e.dragging = true
+
+  e.pick(evt)
+}
+
+func (e *colorSVArea) onMouseMove(evt *Event) {
+  if e.dragging {
+    e.pick(evt)
+  }
+}
+
+func (e *colorSVArea) onMouseUp(evt *Event) {
+  e.dragging = false
+}
+
+func (e *colorSVArea) Cursor(x, y int) int {
+  return sdl.SYSTEM_CURSOR_CROSSHAIR
+}
+
+func (e *colorSVArea) Show() {
+  e.setTypesAndColors()
+
+  e.ElementData.Show()
+}
+
+func (e *colorSVArea) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := maxWidth, maxHeight
+
+  z := e.Z(maxZIndex)
+  dz := 0.5/float32(maxZIndex)
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, h}, z)
+  e.Root.P1.SetQuadPos(e.p1Tris[2], e.p1Tris[3], Rect{0, 0, w, h}, z - dz)
+
+  picker := e.popup.picker
+
+  ms := COLORPOPUP_MARKER_SIZE
+  x := int(picker.sat*float64(w)) - ms/2
+  y := int((1.0 - picker.val)*float64(h)) - ms/2
+
+  setOutlinePos(e.Root.P1, e.p1Tris[4:], Rect{x, y, ms, ms}, 1, z - 2*dz)
+
+  return e.InitRect(w, h)
+}
+
+// 6 quads with the hue going from red at the top back to red at the bottom, the last quad is the marker
+type colorHueBar struct {
+  ElementData
+
+  popup    *colorPopup
+  dragging bool
+}
+
+func newColorHueBar(popup *colorPopup) *colorHueBar {
+  e := &colorHueBar{
+    NewElementData(7*2, 0),
+    popup,
+    false,
+  }
+
+  e.setTypesAndColors()
+
+  e.On("mousedown", e.onMouseDown)
+  e.On("mousemove", e.onMouseMove)
+  e.On("mouseup",   e.onMouseUp)
+
+  return e
+}
+
+func (e *colorHueBar) setTypesAndColors() {
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+  }
+
+  for i := 0; i < 6; i++ {
+    cTop := hsvToRGB(float64(i)*60.0, 1.0, 1.0)
+    cBottom := hsvToRGB(float64(i + 1)*60.0, 1.0, 1.0)
+
+    e.Root.P1.SetQuadColorLinearVGrad(e.p1Tris[i*2], e.p1Tris[i*2 + 1], cTop, cBottom)
+  }
+
+  e.Root.P1.SetColorConst(e.p1Tris[12], BLACK)
+  e.Root.P1.SetColorConst(e.p1Tris[13], BLACK)
+}
+
+func (e *colorHueBar) pick(evt *Event) {
+  picker := e.popup.picker
+
+  h := clampUnit(float64(evt.Y - e.rect.Y)/float64(e.rect.H))*360.0
+  if h == 360.0 {
+    h = 0.0
+  }
+
+  picker.setHSV(h, picker.sat, picker.val)
+}
+
+func (e *colorHueBar) onMouseDown(evt *Event) {
+  e.dragging = true
+
+  e.pick(evt)
+}
+
+func (e *colorHueBar) onMouseMove(evt *Event) {
+  if e.dragging {
+    e.pick(evt)
+  }
+}
+
+func (e *colorHueBar) onMouseUp(evt *Event) {
+  e.dragging = false
+}
+
+func (e *colorHueBar) Show() {
+  e.setTypesAndColors()
+
+  e.ElementData.Show()
+}
+
+func (e *colorHueBar) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := maxWidth, maxHeight
+
+  z := e.Z(maxZIndex)
+
+  for i := 0; i < 6; i++ {
+    y0 := i*h/6
+    y1 := (i + 1)*h/6
+
+    e.Root.P1.SetQuadPos(e.p1Tris[i*2], e.p1Tris[i*2 + 1], Rect{0, y0, w, y1 - y0}, z)
+  }
+
+  y := int(e.popup.picker.hue/360.0*float64(h))
+
+  e.Root.P1.SetQuadPos(e.p1Tris[12], e.p1Tris[13], Rect{0, y - 1, w, 2}, z - 0.5/float32(maxZIndex))
+
+  return e.InitRect(w, h)
+}
+
+// one of the recent colors
+type colorSwatch struct {
+  ElementData
+
+  popup *colorPopup
+  color sdl.Color
+}
+
+func newColorSwatch(popup *colorPopup, c sdl.Color) *colorSwatch {
+  e := &colorSwatch{
+    NewElementData(2, 0),
+    popup,
+    c,
+  }
+
+  e.setTypesAndColor()
+
+  e.On("click", e.onMouseClick)
+
+  return e
+}
+
+func (e *colorSwatch) setTypesAndColor() {
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri, e.color)
+  }
+}
+
+func (e *colorSwatch) onMouseClick(evt *Event) {
+  e.popup.picker.SetValue(e.color)
+}
+
+func (e *colorSwatch) Cursor(x, y int) int {
+  return sdl.SYSTEM_CURSOR_HAND
+}
+
+func (e *colorSwatch) Show() {
+  e.setTypesAndColor()
+
+  e.ElementData.Show()
+}
+
+func (e *colorSwatch) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, maxWidth, maxHeight}, e.Z(maxZIndex))
+
+  return e.InitRect(maxWidth, maxHeight)
+}
+
+// 4 quads, starting at tris[0]
+func setOutlinePos(d *DrawPass1Data, tris []uint32, r Rect, t int, z float32) {
+  d.SetQuadPos(tris[0], tris[1], Rect{r.X, r.Y, r.W, t}, z)
+  d.SetQuadPos(tris[2], tris[3], Rect{r.X, r.Bottom() - t, r.W, t}, z)
+  d.SetQuadPos(tris[4], tris[5], Rect{r.X, r.Y + t, t, r.H - 2*t}, z)
+  d.SetQuadPos(tris[6], tris[7], Rect{r.Right() - t, r.Y + t, t, r.H - 2*t}, z)
+}
+
+func clampUnit(f float64) float64 {
+  if f < 0.0 {
+    return 0.0
+  } else if f > 1.0 {
+    return 1.0
+  } else {
+    return f
+  }
+}
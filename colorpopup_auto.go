@@ -0,0 +1,27 @@
+package glui
+func (e *colorPopup) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *colorPopup) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *colorPopup) On(name string, fn EventListener) *colorPopup {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
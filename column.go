@@ -1,6 +1,9 @@
 package glui
 
 import (
+  "fmt"
+  "strconv"
+  "strings"
   "time"
 )
 
@@ -17,32 +20,74 @@ const (
 type Column interface {
   Element
 
-  AddRow(x interface{})
+  // text shown for a value of the TableModel
+  Format(x interface{}) string
 
-  SetSortState(ss SortState)
+  // compares values of the TableModel
+  Less(a, b interface{}) bool
+
+  // converts the text of the cell editor into a value for the TableModel
+  Parse(s string) (interface{}, error)
 
-  Less(i, j int) bool
-  Len() int
-  Swap(i, j int)
+  newCell() Element // implemented by BasicColumn for Text cells
+  setCell(cell Element, x interface{}, selected bool) // implemented by BasicColumn for Text cells
 
-  Select(i int, keepOld bool)
-  Deselect(i int)
-  ClearSelection()
+  SetSortState(ss SortState)
 
   Head() *Button
+
+  column() *BasicColumn // implemented by BasicColumn
+  appendChild(children ...Element) Element // implemented by the concrete column type
 }
 
+// only the cells of the visible rows are materialized, they are recycled when scrolling
 type BasicColumn struct {
   ElementData
 
   align Align
+  font  string
 
   sortState SortState
 
+  self  Column // concrete column type, which embeds this BasicColumn
   table *Table // reference to parent Table
+  col   int    // column index in the TableModel
   head  *Button
+  caption *Text
   arrow *Icon
-  body  []*Text
+  prio  *Text // sort priority
+  grip  *columnGrip // created by Table.A()
+  cells []Element
+  first int // view row of the first cell
+  n     int // number of rows shown, the cells of empty values are hidden
+
+  userWidth int  // set by dragging the grip, -1 for automatic
+  contentW  int  // width of the widest cell shown so far, so the width doesn't change while scrolling, reset when rows are removed
+  hidden    bool // hidden by the user
+
+  editable  bool
+  validator func(x interface{}) error
+  onCommit  func(row int, x interface{}) // row in the TableModel
+
+  filter string // lower case, rows are only shown if the formatted cell contains this
+}
+
+// nil and blank strings are shown as empty cells, and sorted first
+func isEmptyCell(x interface{}) bool {
+  if x == nil {
+    return true
+  } else if s, ok := x.(string); ok {
+    return strings.TrimSpace(s) == ""
+  } else {
+    return false
+  }
+}
+
+// for Less() implementations, ok is false if neither a nor b is empty
+func lessEmptyCell(a, b interface{}) (bool, bool) {
+  aEmpty, bEmpty := isEmptyCell(a), isEmptyCell(b)
+
+  return aEmpty && !bEmpty, aEmpty || bEmpty
 }
 
 func (e *BasicColumn) lineH() int {
@@ -51,23 +96,31 @@ func (e *BasicColumn) lineH() int {
 
 type TextColumn struct {
   BasicColumn
-  rows []string
 }
 
 type DateColumn struct {
   BasicColumn
-  rows []time.Time
 }
 
 func NewBasicColumn(align Align) *BasicColumn {
   c := &BasicColumn{
     NewElementData(0, 0),
     align,
+    DEFAULT_SANS,
     UNSORTED,
+    nil, // set by concrete type
     nil, // registered later
+    -1,  // set by Table.A()
     nil,
     nil,
-    make([]*Text, 0),
+    nil,
+    nil,
+    nil,
+    make([]Element, 0),
+    0, 0,
+    -1, 0, false,
+    true, nil, nil,
+    "",
   }
 
   c.padding = [4]int{0, DEFAULT_COLUMN_PADDING, 0, DEFAULT_COLUMN_PADDING}
@@ -75,28 +128,12 @@ func NewBasicColumn(align Align) *BasicColumn {
   return c
 }
 
-func (e *BasicColumn) Head() *Button {
-  return e.head
-}
-
-func (e *BasicColumn) Select(i int, keepOld bool) {
-  for j, txt := range e.body {
-    if i == j {
-      txt.SetColor(WHITE)
-    } else if !keepOld {
-      txt.SetColor(BLACK)
-    }
-  }
-}
-
-func (e *BasicColumn) Deselect(i int) {
-  e.body[i].SetColor(BLACK)
+func (e *BasicColumn) column() *BasicColumn {
+  return e
 }
 
-func (e *BasicColumn) ClearSelection() {
-  for _, txt := range e.body {
-    txt.SetColor(BLACK)
-  }
+func (e *BasicColumn) Head() *Button {
+  return e.head
 }
 
 func (e *BasicColumn) SetSortState(ss SortState) {
@@ -114,6 +151,18 @@ func (e *BasicColumn) SetSortState(ss SortState) {
   }
 }
 
+// 0 hides the priority
+func (e *BasicColumn) setSortPriority(p int) {
+  content := ""
+  if p > 0 {
+    content = strconv.Itoa(p)
+  }
+
+  if content != e.prio.Value() {
+    e.prio.SetContent(content)
+  }
+}
+
 func (e *BasicColumn) RegisterParent(parent Element) {
   tb, ok := parent.(*tableBody)
   if !ok {
@@ -125,11 +174,41 @@ func (e *BasicColumn) RegisterParent(parent Element) {
   e.ElementData.RegisterParent(parent)
 }
 
-func (e *BasicColumn) Len() int {
-  return len(e.body)
+// cells can only be edited if the TableModel is an EditableTableModel, or if there is a commit callback
+func (e *BasicColumn) SetEditable(b bool) {
+  e.editable = b
+}
+
+// called with the parsed value before committing, a non-nil error keeps the editor open
+func (e *BasicColumn) SetValidator(fn func(x interface{}) error) {
+  e.validator = fn
+}
+
+// called after the edited value is written to the model
+func (e *BasicColumn) OnCommit(fn func(row int, x interface{})) {
+  e.onCommit = fn
+}
+
+// width that fits the head and the cells shown so far
+func (e *BasicColumn) fitWidth() int {
+  w := e.caption.Rect().W + 2*DEFAULT_COLUMN_PADDING + 2*e.Root.P1.Skin.ButtonBorderThickness()
+
+  if e.arrow.Visible() {
+    w += e.arrow.Rect().W
+  }
+
+  if e.contentW > w {
+    w = e.contentW
+  }
+
+  return w
 }
 
 func (e *BasicColumn) Show() {
+  if e.hidden {
+    return
+  }
+
   e.ElementData.Show()
 
   if e.sortState != ASCENDING && e.sortState != DESCENDING {
@@ -137,44 +216,129 @@ func (e *BasicColumn) Show() {
   } 
 }
 
+func (e *BasicColumn) newCell() Element {
+  return NewText("", e.font, 10)
+}
+
+func (e *BasicColumn) setCell(cell Element, x interface{}, selected bool) {
+  txt := cell.(*Text)
+
+  content := e.self.Format(x)
+  if content != txt.Value() {
+    txt.SetContent(content)
+  }
+
+  if selected {
+    txt.SetColor(WHITE)
+  } else {
+    txt.SetColor(BLACK)
+  }
+}
+
+// shows the rows first up to first+n of the table view
+func (e *BasicColumn) setRows(first, n int) {
+  for ; len(e.cells) < n; {
+    cell := e.self.newCell()
+    e.cells = append(e.cells, cell)
+    e.self.appendChild(cell)
+
+    // cells are added during CalcPos
+    e.Root.calcNewDepth(cell)
+  }
+
+  e.first = first
+  e.n = n
+
+  body := e.table.body
+
+  for j, cell := range e.cells {
+    var x interface{} = nil
+    if j < n {
+      x = e.table.model.Cell(body.order[first + j], e.col)
+    }
+
+    if j >= n || isEmptyCell(x) {
+      if cell.Visible() {
+        cell.Hide()
+      }
+
+      continue
+    } else if !cell.Visible() {
+      cell.Show()
+    }
+
+    row := first + j
+
+    e.self.setCell(cell, x, body.showSel && body.selState[row])
+  }
+}
+
+// a width set by the user is fixed, and the cells are cropped
 func (e *BasicColumn) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
-  y := 0
+  fixed := e.userWidth >= 0
+  if fixed {
+    maxWidth = e.userWidth
+  }
+
+  // hidden cells of empty values still take up their row
+  visible := make([]Element, 0)
+  visibleRows := make([]int, 0)
+  for j, cell := range e.cells {
+    if cell.Visible() {
+      visible = append(visible, cell)
+      visibleRows = append(visibleRows, j)
+    }
+  }
 
-  textWidths := make([]int, len(e.body))
-  for i, textElem := range e.body {
-    w, h := textElem.CalcPos(maxWidth - e.padding[1] - e.padding[3], e.lineH(), maxZIndex)
+  // the width only grows while new cells are shown
+  cellWidths := make([]int, len(visible))
+  for i, cell := range visible {
+    w, h := calcPos(cell, maxWidth - e.padding[1] - e.padding[3], e.lineH(), maxZIndex)
 
     wPlusPadding := w + e.padding[1] + e.padding[3]
 
-    if wPlusPadding > maxWidth {
-      maxWidth = wPlusPadding
+    if wPlusPadding > e.contentW {
+      e.contentW = wPlusPadding
     }
 
-    textWidths[i] = w
+    cellWidths[i] = w
 
-    textElem.Translate(0, y + (e.lineH() - h)/2)
+    cell.Translate(0, visibleRows[i]*e.lineH() + (e.lineH() - h)/2)
+  }
 
-    y += e.lineH()
+  y := e.n*e.lineH()
+
+  if e.contentW > maxWidth && !fixed {
+    maxWidth = e.contentW
+  }
+
+  align := e.align
+  if isRTL() {
+    align = mirrorAlign(align)
   }
 
-  for i, textElem := range e.body {
-    w := textWidths[i]
+  for i, cell := range visible {
+    w := cellWidths[i]
     dx := e.padding[3]
 
-    switch e.align {
+    switch align {
     case CENTER:
       dx = (maxWidth - w)/2
     case END:
       dx = maxWidth - w - e.padding[1]
     }
 
-    textElem.Translate(dx, 0)
+    cell.Translate(dx, 0)
+  }
+
+  if fixed && e.contentW > maxWidth {
+    e.Crop(Rect{0, 0, maxWidth, y})
   }
 
   return e.InitRect(maxWidth, y)
 }
 
-func newHeadButton(caption string) (*Button, *Icon) {
+func newHeadButton(caption string) (*Button, *Text, *Icon, *Text) {
   b := NewButton()
 
   hor := NewHor(STRETCH, CENTER, 0).H(-1)
@@ -183,11 +347,14 @@ func newHeadButton(caption string) (*Button, *Icon) {
   icon := NewIcon("arrow-down-drop", 10)
   icon.Hide() // i.e. UNSORTED
 
-  hor.A(NewSans(caption, 10), icon)
+  text := NewSans(caption, 10)
+  prio := NewSans("", 8)
+
+  hor.A(text, icon, prio)
 
   b.A(hor)
 
-  return b, icon
+  return b, text, icon, prio
 }
 
 func NewTextColumn(caption string) *TextColumn {
@@ -195,94 +362,89 @@ func NewTextColumn(caption string) *TextColumn {
 
   e := &TextColumn{
     *e_,
-    make([]string, 0),
   }
 
-  e.head, e.arrow = newHeadButton(caption)
+  e.self = e
+  e.head, e.caption, e.arrow, e.prio = newHeadButton(caption)
   e.head.OnClick(e.onClickSort)
 
   return e
 }
 
-func (e *TextColumn) AddRow(x_ interface{}) {
-  x, ok := x_.(string)
-  if !ok {
-    panic("row entry is not a string")
+func (e *TextColumn) Format(x interface{}) string {
+  if s, ok := x.(string); ok {
+    return s
+  } else if x == nil {
+    return ""
+  } else {
+    return fmt.Sprint(x)
   }
-
-  txt := NewSans(x, 10)
-  e.rows = append(e.rows, x)
-  e.body = append(e.body, txt)
-  e.appendChild(txt)
-}
-
-func (e *TextColumn) onClickSort() {
-  e.table.SortByColumn(e, NextSortState(e.sortState))
 }
 
-func (e *BasicColumn) swap(i, j int) {
-  e.body[i], e.body[j] = e.body[j], e.body[i]
-
-  e.children[i], e.children[j] = e.children[j], e.children[i]
+func (e *TextColumn) Less(a, b interface{}) bool {
+  return e.Format(a) < e.Format(b)
 }
 
-func (e *TextColumn) Less(i, j int) bool {
-  return e.rows[i] < e.rows[j]
+func (e *TextColumn) Parse(s string) (interface{}, error) {
+  return s, nil
 }
 
-func (e *TextColumn) Swap(i, j int) {
-  e.rows[i], e.rows[j] = e.rows[j], e.rows[i]
-
-  e.BasicColumn.swap(i, j)
+func (e *TextColumn) onClickSort() {
+  e.table.clickSort(e)
 }
 
 func NewDateColumn(caption string) *DateColumn {
   e_ := NewBasicColumn(END)
 
   e := &DateColumn{
-    *e_, 
-    make([]time.Time, 0),
+    *e_,
   }
 
-  e.head, e.arrow = newHeadButton(caption)
+  e.self = e
+  e.font = DEFAULT_MONO
+  e.head, e.caption, e.arrow, e.prio = newHeadButton(caption)
   e.head.OnClick(e.onClickSort)
 
   return e
 }
 
-func (e *DateColumn) AddRow(x_ interface{}) {
-  var t time.Time
-
-  switch x := x_.(type) {
+// values can be time.Time or strings formatted like DATE_FMT
+func toTime(x interface{}) time.Time {
+  switch t := x.(type) {
   case time.Time:
-    t = x
+    return t
   case string:
-    var err error
-    t, err = time.Parse(DATE_FMT, x)
+    res, err := time.Parse(DATE_FMT, t)
     if err != nil {
       panic(err)
     }
 
+    return res
   default:
-    panic("row entry is not time.Time")
+    panic("value is not time.Time")
   }
-
-  e.rows = append(e.rows, t)
-  txt := NewMono(t.Format(DATE_FMT), 10)
-  e.body = append(e.body, txt)
-  e.appendChild(txt)
 }
 
-func (e *DateColumn) onClickSort() {
-  e.table.SortByColumn(e, NextSortState(e.sortState))
+func (e *DateColumn) Format(x interface{}) string {
+  if isEmptyCell(x) {
+    return ""
+  }
+
+  return toTime(x).Format(DATE_FMT)
 }
 
-func (e *DateColumn) Less(i, j int) bool {
-  return e.rows[i].Before(e.rows[j])
+func (e *DateColumn) Less(a, b interface{}) bool {
+  if less, ok := lessEmptyCell(a, b); ok {
+    return less
+  }
+
+  return toTime(a).Before(toTime(b))
 }
 
-func (e *DateColumn) Swap(i, j int) {
-  e.rows[i], e.rows[j] = e.rows[j], e.rows[i]
+func (e *DateColumn) Parse(s string) (interface{}, error) {
+  return time.Parse(DATE_FMT, strings.TrimSpace(s))
+}
 
-  e.BasicColumn.swap(i, j)
+func (e *DateColumn) onClickSort() {
+  e.table.clickSort(e)
 }
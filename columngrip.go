@@ -0,0 +1,93 @@
+package glui
+
+import (
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element columnGrip "CalcDepth On"
+
+const (
+  COLUMN_GRIP_WIDTH = 6
+  COLUMN_MIN_WIDTH  = 20
+)
+
+// invisible handle on the border between two head buttons, dragging it resizes the column
+type columnGrip struct {
+  ElementData
+
+  column Column
+
+  // state
+  active bool
+  startX int
+  startW int
+}
+
+func newColumnGrip(column Column) *columnGrip {
+  e := &columnGrip{
+    NewElementData(0, 0),
+    column,
+    false, 0, 0,
+  }
+
+  e.On("mousedown",   e.onMouseDown)
+  e.On("mousemove",   e.onMouseMove)
+  e.On("mouseup",     e.onMouseUp)
+  e.On("doubleclick", e.onDoubleClick)
+
+  return e
+}
+
+func (e *columnGrip) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  return e.InitRect(COLUMN_GRIP_WIDTH, maxHeight)
+}
+
+func (e *columnGrip) Cursor(x, y int) int {
+  return sdl.SYSTEM_CURSOR_SIZEWE
+}
+
+func (e *columnGrip) onMouseDown(evt *Event) {
+  e.active = true
+  e.startX = evt.X
+  e.startW = e.column.Rect().W
+
+  evt.StopBubbling()
+}
+
+func (e *columnGrip) onMouseMove(evt *Event) {
+  if !e.active {
+    return
+  }
+
+  dx := evt.X - e.startX
+  if isRTL() {
+    dx = -dx
+  }
+
+  w := e.startW + dx
+  if w < COLUMN_MIN_WIDTH {
+    w = COLUMN_MIN_WIDTH
+  }
+
+  c := e.column.column()
+  if c.userWidth != w {
+    c.userWidth = w
+
+    e.Root.ForceElementPosDirty(c.table)
+  }
+}
+
+func (e *columnGrip) onMouseUp(evt *Event) {
+  e.active = false
+}
+
+// autofit
+func (e *columnGrip) onDoubleClick(evt *Event) {
+  c := e.column.column()
+
+  c.userWidth = c.fitWidth()
+
+  e.Root.ForceElementPosDirty(c.table)
+
+  evt.StopBubbling()
+}
@@ -0,0 +1,241 @@
+package glui
+
+import (
+)
+
+const COLUMN_DRAG_THRESHOLD = 5
+
+// order, widths and visibility of the columns of a Table, in terms of the TableModel column indices,
+//  so it can be saved and later restored with Table.SetColumnLayout()
+type ColumnLayout struct {
+  Order  []int  // model columns in display order
+  Widths []int  // indexed by model column, -1 for automatic
+  Hidden []bool // indexed by model column
+}
+
+func (e *Table) ColumnLayout() ColumnLayout {
+  nc := e.body.nColumns()
+
+  l := ColumnLayout{make([]int, 0, nc), make([]int, nc), make([]bool, nc)}
+
+  for i := 0; i < nc; i++ {
+    c := e.body.getColumn(i).column()
+
+    l.Order = append(l.Order, c.col)
+    l.Widths[c.col] = c.userWidth
+    l.Hidden[c.col] = c.hidden
+  }
+
+  return l
+}
+
+func (e *Table) SetColumnLayout(l ColumnLayout) {
+  nc := e.body.nColumns()
+
+  if len(l.Order) != nc || len(l.Widths) != nc || len(l.Hidden) != nc {
+    panic("column layout doesn't match the table")
+  }
+
+  byCol := make([]Column, nc)
+  for i := 0; i < nc; i++ {
+    c := e.body.getColumn(i)
+
+    byCol[c.column().col] = c
+  }
+
+  for i, col := range l.Order {
+    e.MoveColumn(byCol[col], i)
+  }
+
+  for col, c := range byCol {
+    c.column().userWidth = l.Widths[col]
+
+    e.SetColumnHidden(c, l.Hidden[col])
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// display index
+func (e *Table) columnIndex(c Column) int {
+  for i := 0; i < e.body.nColumns(); i++ {
+    if e.body.getColumn(i) == c {
+      return i
+    }
+  }
+
+  panic("column not part of table")
+}
+
+// i is the new display index of c
+func (e *Table) MoveColumn(c Column, i int) {
+  from := e.columnIndex(c)
+  if from == i {
+    return
+  }
+
+  head := e.head[from]
+
+  cols := append(e.body.children[0:from:from], e.body.children[from+1:]...)
+  e.body.children = append(cols[0:i:i], append([]Element{c}, cols[i:]...)...)
+
+  heads := append(e.head[0:from:from], e.head[from+1:]...)
+  e.head = append(heads[0:i:i], append([]*Button{head}, heads[i:]...)...)
+
+  e.orderChildren()
+
+  e.Root.ForceElementDepthDirty(e.body)
+  e.Root.ForceElementPosDirty(e)
+}
+
+// at least one column should remain visible
+func (e *Table) SetColumnHidden(c Column, hidden bool) {
+  bc := c.column()
+  if bc.hidden == hidden {
+    return
+  }
+
+  head := e.head[e.columnIndex(c)]
+
+  bc.hidden = hidden
+
+  if hidden {
+    c.Hide()
+    head.Hide()
+    bc.grip.Hide()
+  } else {
+    head.Show()
+    bc.grip.Show()
+    c.Show()
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *Table) onMouseDownHead(c Column, evt *Event) {
+  e.dragCol = c
+  e.dragStartX = evt.X
+  e.headDragged = false
+}
+
+func (e *Table) onMouseMoveHead(evt *Event) {
+  if e.dragCol == nil {
+    return
+  }
+
+  if !e.marker.Visible() {
+    dx := evt.X - e.dragStartX
+    if dx < COLUMN_DRAG_THRESHOLD && dx > -COLUMN_DRAG_THRESHOLD {
+      return
+    }
+
+    e.marker.Show()
+  }
+
+  e.updateDragTo(evt.X)
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *Table) onMouseUpHead(evt *Event) {
+  if e.dragCol == nil {
+    return
+  }
+
+  if e.marker.Visible() {
+    e.marker.Hide()
+
+    to := e.dragTo
+    if to > e.columnIndex(e.dragCol) {
+      to--
+    }
+
+    e.MoveColumn(e.dragCol, to)
+
+    e.headDragged = true
+  }
+
+  e.dragCol = nil
+}
+
+func (e *Table) onKeyDownHead(evt *Event) {
+  e.headDragged = false
+  e.sortShift = evt.Shift
+}
+
+// runs before the click handler of the head button
+func (e *Table) onClickHead(evt *Event) {
+  e.sortShift = evt.Shift
+}
+
+// the dragged column is inserted before the first column whose center is past x
+func (e *Table) updateDragTo(x int) {
+  br := e.body.Rect()
+
+  e.dragTo = 0
+  edge := br.X
+  if isRTL() {
+    edge = br.Right()
+  }
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    c := e.body.getColumn(i)
+    if c.column().hidden {
+      continue
+    }
+
+    r := c.Rect()
+
+    if isRTL() {
+      if x < r.X + r.W/2 {
+        e.dragTo = i + 1
+        edge = r.X
+      }
+    } else if x > r.X + r.W/2 {
+      e.dragTo = i + 1
+      edge = r.Right()
+    }
+  }
+
+  e.dragX = edge - e.rect.X
+}
+
+func (e *Table) onRightClick(evt *Event) {
+  if evt.Y >= e.body.Rect().Y {
+    e.onRightClickBody(evt)
+    return
+  }
+
+  e.fillHeadMenu()
+
+  e.Root.Menu.ShowAt(
+    e,
+    float64(evt.X - e.rect.X)/float64(e.rect.W),
+    float64(evt.Y - e.rect.Y)/float64(e.rect.H),
+    150,
+  )
+}
+
+func (e *Table) fillHeadMenu() {
+  e.Root.Menu.ClearChildren()
+
+  bh := 30
+
+  nVisible := len(e.body.visibleColumns())
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    c := e.body.getColumn(i)
+    bc := c.column()
+
+    caption := "Hide " + bc.caption.Value()
+    if bc.hidden {
+      caption = "Show " + bc.caption.Value()
+    }
+
+    item := NewMenuItem(caption, func() {
+      e.SetColumnHidden(c, !c.column().hidden)
+    }).H(bh)
+
+    e.Root.Menu.AddItem(item, bc.hidden || nVisible > 1, false)
+  }
+}
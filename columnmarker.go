@@ -0,0 +1,38 @@
+package glui
+
+import (
+)
+
+//go:generate ./gen_element columnMarker "CalcDepth"
+
+const COLUMN_MARKER_WIDTH = 2
+
+// vertical line that shows where a dragged column will be inserted
+type columnMarker struct {
+  ElementData
+}
+
+func newColumnMarker() *columnMarker {
+  e := &columnMarker{
+    NewElementData(2, 0),
+  }
+
+  e.Hide()
+
+  return e
+}
+
+func (e *columnMarker) Show() {
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri, e.Root.P1.Skin.SelColor())
+  }
+
+  e.ElementData.Show()
+}
+
+func (e *columnMarker) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, COLUMN_MARKER_WIDTH, maxHeight}, e.Z(maxZIndex))
+
+  return e.InitRect(COLUMN_MARKER_WIDTH, maxHeight)
+}
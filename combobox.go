@@ -0,0 +1,385 @@
+package glui
+
+import (
+  "sort"
+  "strings"
+)
+
+//go:generate ./gen_element ComboBox "CalcDepth appendChild On Size"
+
+type ComboBoxMode int
+
+const (
+  COMBOBOX_PREFIX ComboBoxMode = iota // options starting with the typed text
+  COMBOBOX_FUZZY                      // options containing the typed runes in order
+)
+
+const (
+  COMBOBOX_MAX_SUGGESTIONS = 50
+  COMBOBOX_PAGE_SIZE       = 10 // suggestions skipped by pageup and pagedown
+)
+
+// done can be called from any goroutine, results of older queries are ignored
+type SuggestionProvider func(query string, done func(suggestions []string))
+
+// Input with a button that shows the suggestions in the frame menu
+// the text doesn't have to be one of the options
+type ComboBox struct {
+  ElementData
+
+  input  *Input
+  button *Button
+
+  options  []string
+  mode     ComboBoxMode
+  provider SuggestionProvider // nil to filter the options
+
+  value   string
+  queryID int // incremented for every query, so late results can be dropped
+
+  onChange func(v string)
+}
+
+func NewComboBox(options []string) *ComboBox {
+  e := &ComboBox{
+    NewElementData(0, 0),
+    NewInput(),
+    NewIconButton("arrow-down-drop", 10, HOR),
+    options,
+    COMBOBOX_PREFIX,
+    nil,
+    "",
+    0,
+    nil,
+  }
+
+  e.width, e.height = 200, 30
+
+  e.appendChild(e.input, e.button)
+
+  // the input keeps the focus while the suggestions are shown
+  delete(e.button.evtListeners, "focus")
+
+  e.button.OnClick(e.toggleSuggestions)
+
+  // before the input itself, so the arrow keys can be taken over while the suggestions are shown
+  e.input.On("keypress",  e.onKeyInput)
+  e.input.On("textinput", e.onTextInput)
+  e.input.On("blur",      e.onBlurInput)
+
+  e.On("mousebuttonoutsidemenu", e.onMouseButtonOutsideMenu)
+
+  return e
+}
+
+func (e *ComboBox) OnChange(fn func(v string)) *ComboBox {
+  e.onChange = fn
+
+  return e
+}
+
+func (e *ComboBox) Mode(mode ComboBoxMode) *ComboBox {
+  e.mode = mode
+
+  return e
+}
+
+// replaces the filtering of the options
+func (e *ComboBox) Provider(fn SuggestionProvider) *ComboBox {
+  e.provider = fn
+
+  return e
+}
+
+func (e *ComboBox) SetOptions(options []string) {
+  e.options = options
+
+  if e.suggestionsVisible() {
+    e.query()
+  }
+}
+
+func (e *ComboBox) Value() string {
+  return e.value
+}
+
+func (e *ComboBox) SetValue(v string) {
+  changed := v != e.value
+
+  e.value = v
+
+  e.input.SetValue(v)
+
+  if changed && e.onChange != nil {
+    e.onChange(v)
+  }
+}
+
+func (e *ComboBox) commit() {
+  if e.input.Value() != e.value {
+    e.SetValue(e.input.Value())
+  }
+}
+
+func (e *ComboBox) suggestionsVisible() bool {
+  return e.Root.Menu.IsOwnedBy(e)
+}
+
+func (e *ComboBox) hideSuggestions() {
+  // drop the results of pending queries
+  e.queryID += 1
+
+  if e.suggestionsVisible() {
+    e.Root.Menu.Hide()
+  }
+}
+
+// the button shows all the suggestions, regardless of the typed text
+func (e *ComboBox) toggleSuggestions() {
+  if e.suggestionsVisible() {
+    e.hideSuggestions()
+  } else {
+    e.queryText("")
+  }
+
+  Focus(e.input)
+}
+
+func (e *ComboBox) query() {
+  e.queryText(e.input.Value())
+}
+
+func (e *ComboBox) queryText(q string) {
+  e.queryID += 1
+
+  if e.provider == nil {
+    e.showSuggestions(filterOptions(e.options, q, e.mode))
+    return
+  }
+
+  id := e.queryID
+
+  e.provider(q, func(suggestions []string) {
+    postCallback(func() {
+      if id == e.queryID && e.Visible() {
+        e.showSuggestions(suggestions)
+      }
+    })
+  })
+}
+
+func (e *ComboBox) showSuggestions(suggestions []string) {
+  if len(suggestions) == 0 {
+    if e.suggestionsVisible() {
+      e.Root.Menu.Hide()
+    }
+
+    return
+  }
+
+  if len(suggestions) > COMBOBOX_MAX_SUGGESTIONS {
+    suggestions = suggestions[0:COMBOBOX_MAX_SUGGESTIONS]
+  }
+
+  menu := e.Root.Menu
+
+  menu.ClearChildren()
+
+  for _, s := range suggestions {
+    s_ := s
+
+    item := NewMenuItem(s_, func() {
+      e.choose(s_)
+    }).H(e.height)
+
+    menu.AddItem(item, true, false)
+  }
+
+  menu.ShowAt(e, 0.0, 1.0, e.rect.W)
+}
+
+// called by the menu items
+func (e *ComboBox) choose(s string) {
+  e.hideSuggestions()
+
+  e.SetValue(s)
+}
+
+func (e *ComboBox) onMouseButtonOutsideMenu(evt *Event) {
+  if !e.IsHit(evt.X, evt.Y) {
+    e.hideSuggestions()
+  }
+}
+
+func (e *ComboBox) onKeyInput(evt *Event) {
+  menu := e.Root.Menu
+
+  if !e.suggestionsVisible() {
+    switch evt.Key {
+    case "down":
+      e.query()
+    case "return":
+      e.commit()
+    default:
+      e.editInput(evt)
+    }
+
+    return
+  }
+
+  switch evt.Key {
+  case "down":
+    menu.SelectNext()
+  case "up":
+    menu.SelectPrev()
+  case "pagedown":
+    menu.SelectIndex(menu.SelectedIndex() + COMBOBOX_PAGE_SIZE)
+  case "pageup":
+    menu.SelectIndex(menu.SelectedIndex() - COMBOBOX_PAGE_SIZE)
+  case "return":
+    if menu.selectedItem() != nil {
+      menu.ClickSelected()
+    } else {
+      e.hideSuggestions()
+      e.commit()
+    }
+  case "escape":
+    e.hideSuggestions()
+  default:
+    // e.g. left and right still move the caret
+    e.editInput(evt)
+    return
+  }
+
+  evt.StopPropagation()
+}
+
+// the input handles the key first, so the suggestions are based on the new text
+func (e *ComboBox) editInput(evt *Event) {
+  old := e.input.Value()
+
+  e.input.onKeyPress(evt)
+
+  evt.StopPropagation()
+
+  if e.input.Value() != old {
+    e.query()
+  }
+}
+
+func (e *ComboBox) onTextInput(evt *Event) {
+  e.input.onTextInput(evt)
+
+  evt.StopPropagation()
+
+  e.query()
+}
+
+// clicking the button also blurs the input, so the suggestions are only hidden when tabbing away
+func (e *ComboBox) onBlurInput(evt *Event) {
+  if evt.IsKeyboardEvent() {
+    e.hideSuggestions()
+  }
+
+  e.commit()
+}
+
+func (e *ComboBox) Hide() {
+  e.hideSuggestions()
+
+  e.ElementData.Hide()
+}
+
+func (e *ComboBox) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  if w > maxWidth {
+    w = maxWidth
+  }
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  // square button
+  bw := h
+  iw := w - bw
+
+  e.input.width, e.input.height = iw, h
+  calcPos(e.input, iw, h, maxZIndex)
+
+  e.button.width, e.button.height = bw, h
+  calcPos(e.button, bw, h, maxZIndex)
+
+  // the button is on the left for RTL
+  if isRTL() {
+    e.input.Translate(bw, 0)
+  } else {
+    e.button.Translate(iw, 0)
+  }
+
+  return e.InitRect(w, h)
+}
+
+// case insensitive, fuzzy matches are sorted by score
+func filterOptions(options []string, q string, mode ComboBoxMode) []string {
+  res := make([]string, 0)
+
+  if mode == COMBOBOX_FUZZY {
+    scores := make(map[string]int)
+
+    for _, opt := range options {
+      if score, ok := fuzzyScore(opt, q); ok {
+        scores[opt] = score
+        res = append(res, opt)
+      }
+    }
+
+    sort.SliceStable(res, func(i, j int) bool {
+      return scores[res[i]] < scores[res[j]]
+    })
+  } else {
+    lq := strings.ToLower(q)
+
+    for _, opt := range options {
+      if strings.HasPrefix(strings.ToLower(opt), lq) {
+        res = append(res, opt)
+      }
+    }
+  }
+
+  return res
+}
+
+// all runes of q must appear in s in the same order, lower scores are better matches
+// the score is the position of the first match plus the gaps between the matches
+func fuzzyScore(s string, q string) (int, bool) {
+  qs := []rune(strings.ToLower(q))
+
+  if len(qs) == 0 {
+    return 0, true
+  }
+
+  score := 0
+  prev := -1
+  j := 0
+
+  for i, r := range []rune(strings.ToLower(s)) {
+    if r == qs[j] {
+      if prev == -1 {
+        score += i
+      } else {
+        score += i - prev - 1
+      }
+
+      prev = i
+      j += 1
+
+      if j == len(qs) {
+        return score, true
+      }
+    }
+  }
+
+  return 0, false
+}
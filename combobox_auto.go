@@ -0,0 +1,34 @@
+package glui
+func (e *ComboBox) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *ComboBox) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *ComboBox) On(name string, fn EventListener) *ComboBox {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
+func (e *ComboBox) Size(w, h int) *ComboBox {
+  e.width = w
+  e.height = h
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
@@ -4,6 +4,7 @@ func (e *DateColumn) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -0,0 +1,331 @@
+package glui
+
+import (
+  "strings"
+  "time"
+)
+
+//go:generate ./gen_element DatePicker "CalcDepth appendChild On Size"
+
+// Input for DATE_FMT strings, with a button that shows a calendar in the frame menu
+// the dates are at midnight UTC, like the values parsed by DateColumn
+type DatePicker struct {
+  ElementData
+
+  input  *Input
+  button *Button
+
+  value        time.Time // zero for no date
+  min          time.Time // zero for no minimum
+  max          time.Time // zero for no maximum
+  firstWeekday time.Weekday
+
+  cal *calendar // nil if the calendar isn't shown
+
+  onChange func(t time.Time)
+}
+
+func NewDatePicker() *DatePicker {
+  e := &DatePicker{
+    NewElementData(0, 0),
+    NewInput(),
+    NewIconButton("arrow-down-drop", 10, HOR),
+    time.Time{}, time.Time{}, time.Time{},
+    time.Monday,
+    nil,
+    nil,
+  }
+
+  e.width, e.height = 150, 30
+
+  e.appendChild(e.input, e.button)
+
+  // the input keeps the focus while the calendar is shown
+  delete(e.button.evtListeners, "focus")
+
+  e.button.OnClick(e.toggleCalendar)
+
+  // before the input itself, so the arrow keys can be taken over while the calendar is shown
+  e.input.On("keypress", e.onKeyInput)
+  e.input.On("blur",     e.onBlurInput)
+
+  e.On("mousebuttonoutsidemenu", e.onMouseButtonOutsideMenu)
+
+  return e
+}
+
+func (e *DatePicker) OnChange(fn func(t time.Time)) *DatePicker {
+  e.onChange = fn
+
+  return e
+}
+
+// zero times for no bounds
+func (e *DatePicker) Range(min, max time.Time) *DatePicker {
+  e.min = toDate(min)
+  e.max = toDate(max)
+
+  if !e.value.IsZero() {
+    e.SetValue(e.value)
+  }
+
+  return e
+}
+
+func (e *DatePicker) FirstWeekday(d time.Weekday) *DatePicker {
+  e.firstWeekday = d
+
+  if e.cal != nil {
+    e.cal.sync()
+  }
+
+  return e
+}
+
+// zero if no date is chosen
+func (e *DatePicker) Value() time.Time {
+  return e.value
+}
+
+// t is clamped to the range, a zero time clears the input
+func (e *DatePicker) SetValue(t time.Time) {
+  t = e.clamp(toDate(t))
+
+  changed := !t.Equal(e.value)
+
+  e.value = t
+
+  if t.IsZero() {
+    e.input.SetValue("")
+  } else {
+    e.input.SetValue(t.Format(DATE_FMT))
+  }
+
+  e.input.SetInvalid(false)
+
+  if changed && e.onChange != nil {
+    e.onChange(t)
+  }
+}
+
+// midnight UTC of the same day
+func toDate(t time.Time) time.Time {
+  if t.IsZero() {
+    return t
+  }
+
+  y, m, d := t.Date()
+
+  return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// the day is clamped to the length of the target month (AddDate would overflow, e.g. Jan 31 + 1 month is Mar 3)
+func addMonths(t time.Time, n int) time.Time {
+  first := time.Date(t.Year(), t.Month() + time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+
+  d := t.Day()
+  if last := first.AddDate(0, 1, -1).Day(); d > last {
+    d = last
+  }
+
+  return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC)
+}
+
+func today() time.Time {
+  return toDate(time.Now())
+}
+
+func (e *DatePicker) inRange(t time.Time) bool {
+  return (e.min.IsZero() || !t.Before(e.min)) && (e.max.IsZero() || !t.After(e.max))
+}
+
+func (e *DatePicker) clamp(t time.Time) time.Time {
+  if t.IsZero() {
+    return t
+  } else if !e.min.IsZero() && t.Before(e.min) {
+    return e.min
+  } else if !e.max.IsZero() && t.After(e.max) {
+    return e.max
+  } else {
+    return t
+  }
+}
+
+// keeps the text if it can't be parsed, so it can be corrected
+func (e *DatePicker) commit() {
+  s := strings.TrimSpace(e.input.Value())
+  if s == "" {
+    e.SetValue(time.Time{})
+    return
+  }
+
+  t, err := time.Parse(DATE_FMT, s)
+  if err != nil || !e.inRange(t) {
+    e.input.SetInvalid(true)
+    return
+  }
+
+  e.SetValue(t)
+}
+
+func (e *DatePicker) calendarVisible() bool {
+  return e.cal != nil && e.Root.Menu.IsOwnedBy(e)
+}
+
+func (e *DatePicker) toggleCalendar() {
+  if e.calendarVisible() {
+    e.hideCalendar()
+  } else {
+    e.showCalendar()
+  }
+}
+
+// the calendar starts at the typed date, the current value or today
+func (e *DatePicker) showCalendar() {
+  cursor := e.value
+  if t, err := time.Parse(DATE_FMT, strings.TrimSpace(e.input.Value())); err == nil {
+    cursor = t
+  } else if cursor.IsZero() {
+    cursor = e.clamp(today())
+  }
+
+  menu := e.Root.Menu
+
+  menu.ClearChildren()
+
+  e.cal = newCalendar(e, cursor)
+
+  menu.A(e.cal)
+
+  w, h := e.cal.size()
+  t := e.Root.P1.Skin.ButtonBorderThickness()
+
+  menu.height = h + 2*t
+
+  menu.ShowAt(e, 0.0, 1.0, w + 2*t)
+
+  Focus(e.input)
+}
+
+func (e *DatePicker) hideCalendar() {
+  if e.calendarVisible() {
+    e.Root.Menu.Hide()
+  }
+
+  e.cal = nil
+}
+
+// called by the calendar
+func (e *DatePicker) choose(t time.Time) {
+  if !e.inRange(t) {
+    return
+  }
+
+  e.hideCalendar()
+
+  e.SetValue(t)
+}
+
+func (e *DatePicker) onMouseButtonOutsideMenu(evt *Event) {
+  if !e.IsHit(evt.X, evt.Y) {
+    e.hideCalendar()
+  }
+}
+
+func (e *DatePicker) onKeyInput(evt *Event) {
+  if !e.calendarVisible() {
+    switch evt.Key {
+    case "down":
+      e.showCalendar()
+    case "return":
+      e.commit()
+    }
+
+    return
+  }
+
+  left, right := "left", "right"
+  if isRTL() {
+    left, right = right, left
+  }
+
+  c := e.cal.cursor
+
+  switch evt.Key {
+  case left:
+    e.cal.setCursor(c.AddDate(0, 0, -1))
+  case right:
+    e.cal.setCursor(c.AddDate(0, 0, 1))
+  case "up":
+    e.cal.setCursor(c.AddDate(0, 0, -7))
+  case "down":
+    e.cal.setCursor(c.AddDate(0, 0, 7))
+  case "pageup":
+    if evt.Shift {
+      e.cal.setCursor(addMonths(c, -12))
+    } else {
+      e.cal.setCursor(addMonths(c, -1))
+    }
+  case "pagedown":
+    if evt.Shift {
+      e.cal.setCursor(addMonths(c, 12))
+    } else {
+      e.cal.setCursor(addMonths(c, 1))
+    }
+  case "home":
+    e.cal.setCursor(e.cal.month)
+  case "end":
+    e.cal.setCursor(e.cal.month.AddDate(0, 1, -1))
+  case "return":
+    e.choose(c)
+  case "escape":
+    e.hideCalendar()
+  default:
+    // e.g. backspace is still handled by the input
+    return
+  }
+
+  evt.StopPropagation()
+}
+
+// the calendar is closed by clicking elsewhere, so clicking the button can also close it
+func (e *DatePicker) onBlurInput(evt *Event) {
+  e.commit()
+}
+
+func (e *DatePicker) Hide() {
+  e.hideCalendar()
+
+  e.ElementData.Hide()
+}
+
+func (e *DatePicker) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  if w > maxWidth {
+    w = maxWidth
+  }
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  // square button
+  bw := h
+  iw := w - bw
+
+  e.input.width, e.input.height = iw, h
+  calcPos(e.input, iw, h, maxZIndex)
+
+  e.button.width, e.button.height = bw, h
+  calcPos(e.button, bw, h, maxZIndex)
+
+  // the button is on the left for RTL
+  if isRTL() {
+    e.input.Translate(bw, 0)
+  } else {
+    e.button.Translate(iw, 0)
+  }
+
+  return e.InitRect(w, h)
+}
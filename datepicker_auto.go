@@ -0,0 +1,34 @@
+package glui
+func (e *DatePicker) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *DatePicker) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *DatePicker) On(name string, fn EventListener) *DatePicker {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
+func (e *DatePicker) Size(w, h int) *DatePicker {
+  e.width = w
+  e.height = h
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
@@ -0,0 +1,256 @@
+package glui
+
+import (
+  "fmt"
+  "reflect"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+  DEBUG_RECT_COLOR    = sdl.Color{0xff, 0x00, 0x00, 0xff}
+  DEBUG_PADDING_COLOR = sdl.Color{0x00, 0x00, 0xff, 0xff}
+  DEBUG_SPACING_COLOR = sdl.Color{0x00, 0xb0, 0x00, 0xff}
+  DEBUG_HOVER_COLOR   = sdl.Color{0xff, 0xa0, 0x00, 0xff}
+  DEBUG_INFO_BG_COLOR = sdl.Color{0xff, 0xff, 0xe0, 0xff}
+)
+
+// outlines of the rect, padding and spacing of every visible element, toggled with Ctrl+Shift+D
+// plus a readout of the type, size and z-index of the element under the mouse
+type debugOverlay struct {
+  active bool
+  hover  Element
+
+  tris   []uint32 // 2 tris per line, 4 lines per outline
+  bgTris []uint32 // background of info
+  info   *Text // not part of the element tree, allocated on first show
+
+  Root *Frame
+}
+
+type debugOutline struct {
+  r Rect
+  c sdl.Color
+}
+
+func newDebugOverlay(frame *Frame) *debugOverlay {
+  return &debugOverlay{false, nil, make([]uint32, 0), frame.P1.Alloc(2), nil, frame}
+}
+
+func (e *debugOverlay) Show() {
+  if e.info == nil {
+    e.info = NewMono("", 10)
+    e.info.SetColor(BLACK)
+  }
+
+  e.active = true
+
+  e.Root.forceOverlaysPosDirty()
+}
+
+func (e *debugOverlay) Hide() {
+  e.active = false
+
+  e.tris = e.Root.P1.Resize(e.tris, 0)
+
+  for _, tri := range e.bgTris {
+    e.Root.P1.SetTriType(tri, VTYPE_HIDDEN)
+  }
+
+  if e.info != nil {
+    e.info.SetContent("")
+  }
+
+  e.Root.forceOverlaysPosDirty()
+}
+
+func (e *debugOverlay) Toggle() {
+  if e.active {
+    e.Hide()
+  } else {
+    e.Show()
+  }
+}
+
+func (e *debugOverlay) setHover(el Element) {
+  if e.active && e.hover != el {
+    e.hover = el
+
+    e.Root.forceOverlaysPosDirty()
+  }
+}
+
+// inner rect after subtracting padding, padding is ordered top, right, bottom, left
+func debugPaddingRect(r Rect, p [4]int) Rect {
+  return Rect{r.X + p[3], r.Y + p[0], r.W - p[1] - p[3], r.H - p[0] - p[2]}
+}
+
+// gap between two consecutive children, returns false if they aren't side by side
+func debugSpacingRect(a Rect, b Rect) (Rect, bool) {
+  if a.X > b.X {
+    // rtl order
+    a, b = b, a
+  }
+
+  if b.X > a.Right() {
+    return Rect{a.Right(), a.Y, b.X - a.Right(), a.H}, true
+  }
+
+  if a.Y > b.Y {
+    a, b = b, a
+  }
+
+  if b.Y > a.Bottom() {
+    return Rect{a.X, a.Bottom(), a.W, b.Y - a.Bottom()}, true
+  }
+
+  return Rect{}, false
+}
+
+func (e *debugOverlay) collectOutlines(el Element, outlines []debugOutline) []debugOutline {
+  if !el.Visible() {
+    return outlines
+  }
+
+  r := el.Rect()
+  d := el.elementData()
+
+  outlines = append(outlines, debugOutline{r, DEBUG_RECT_COLOR})
+
+  if d.padding != [4]int{0, 0, 0, 0} {
+    outlines = append(outlines, debugOutline{debugPaddingRect(r, d.padding), DEBUG_PADDING_COLOR})
+  }
+
+  var prev Element = nil
+  for _, child := range el.Children() {
+    if !child.Visible() {
+      continue
+    }
+
+    if d.spacing > 0 && prev != nil {
+      if gap, ok := debugSpacingRect(prev.Rect(), child.Rect()); ok {
+        outlines = append(outlines, debugOutline{gap, DEBUG_SPACING_COLOR})
+      }
+    }
+
+    outlines = e.collectOutlines(child, outlines)
+
+    prev = child
+  }
+
+  return outlines
+}
+
+func debugTypeName(el Element) string {
+  t := reflect.TypeOf(el)
+  if t.Kind() == reflect.Ptr {
+    t = t.Elem()
+  }
+
+  return t.Name()
+}
+
+func (e *debugOverlay) CalcPos(maxWidth, maxHeight, maxZIndex int) {
+  if !e.active {
+    return
+  }
+
+  outlines := e.collectOutlines(e.Root.Body, make([]debugOutline, 0))
+  for _, menu := range e.Root.menus() {
+    if menu.Visible() {
+      outlines = e.collectOutlines(menu, outlines)
+    }
+  }
+
+  hasHover := elementNotNil(e.hover) && !e.hover.Deleted() && e.hover.Visible()
+  if hasHover {
+    outlines = append(outlines, debugOutline{e.hover.Rect(), DEBUG_HOVER_COLOR})
+  }
+
+  // above everything, including the menu
+  z := normalizeZIndex(maxZIndex - 2, maxZIndex)
+
+  e.tris = e.Root.P1.Resize(e.tris, len(outlines)*8)
+
+  for i, outline := range outlines {
+    r := outline.r
+
+    lines := [4]Rect{
+      Rect{r.X, r.Y, r.W, 1},
+      Rect{r.X, r.Bottom() - 1, r.W, 1},
+      Rect{r.X, r.Y, 1, r.H},
+      Rect{r.Right() - 1, r.Y, 1, r.H},
+    }
+
+    for j, line := range lines {
+      tri0 := e.tris[i*8 + j*2 + 0]
+      tri1 := e.tris[i*8 + j*2 + 1]
+
+      e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+      e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+      e.Root.P1.SetColorConst(tri0, outline.c)
+      e.Root.P1.SetColorConst(tri1, outline.c)
+      e.Root.P1.SetQuadPos(tri0, tri1, line, z)
+    }
+  }
+
+  e.calcInfoPos(hasHover, maxZIndex)
+}
+
+func (e *debugOverlay) calcInfoPos(hasHover bool, maxZIndex int) {
+  if !hasHover {
+    if e.info.Value() != "" {
+      e.info.SetContent("")
+    }
+
+    for _, tri := range e.bgTris {
+      e.Root.P1.SetTriType(tri, VTYPE_HIDDEN)
+    }
+
+    return
+  }
+
+  r := e.hover.Rect()
+
+  content := fmt.Sprintf("%s %dx%d z=%d", debugTypeName(e.hover), r.W, r.H, e.hover.ZIndex())
+  if content != e.info.Value() {
+    e.info.SetContent(content)
+  }
+
+  // info isn't part of the element tree, so its depth and position are set directly
+  e.info.zIndex = maxZIndex
+  w, h := e.info.CalcPos(e.Root.winW, e.Root.winH, maxZIndex)
+
+  // above the hovered element, or inside if there is no room
+  x, y := r.X, r.Y - h - 2
+  if y < 0 {
+    y = r.Y + 2
+  }
+
+  if x + w + 4 > e.Root.winW {
+    x = e.Root.winW - w - 4
+  }
+
+  if x < 0 {
+    x = 0
+  }
+
+  e.info.Translate(x + 2, y)
+
+  tri0, tri1 := e.bgTris[0], e.bgTris[1]
+
+  e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+  e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+  e.Root.P1.SetColorConst(tri0, DEBUG_INFO_BG_COLOR)
+  e.Root.P1.SetColorConst(tri1, DEBUG_INFO_BG_COLOR)
+  e.Root.P1.SetQuadPos(tri0, tri1, Rect{x, y, w + 4, h + 2}, normalizeZIndex(maxZIndex - 1, maxZIndex))
+}
+
+// toggles the layout debug overlay of the active frame
+func ToggleDebugOverlay() {
+  frame := ActiveFrame()
+
+  frame.debugOverlay.Toggle()
+
+  frame.debugOverlay.setHover(frame.state.mouseElement)
+}
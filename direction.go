@@ -0,0 +1,78 @@
+package glui
+
+import (
+)
+
+type Direction int
+
+const (
+  LTR Direction = iota
+  RTL
+)
+
+// horizontal layout and alignment is mirrored for RTL
+// also determines the base direction of text without strong (i.e. directional) characters
+func SetDirection(d Direction) {
+  app := getApp()
+
+  if app.direction == d {
+    return
+  }
+
+  app.direction = d
+
+  for _, frame := range app.frames {
+    refreshDirection(frame.Body)
+    refreshDirection(frame.Menu)
+
+    frame.ForcePosDirty()
+  }
+}
+
+func GetDirection() Direction {
+  app := getApp()
+
+  return app.direction
+}
+
+func isRTL() bool {
+  return getApp().direction == RTL
+}
+
+func mirrorAlign(a Align) Align {
+  switch a {
+  case START:
+    return END
+  case END:
+    return START
+  default:
+    return a
+  }
+}
+
+func reverseElements(els []Element) []Element {
+  res := make([]Element, len(els))
+
+  for i, el := range els {
+    res[len(els) - 1 - i] = el
+  }
+
+  return res
+}
+
+// the visual order of the glyphs of Text and Input can depend on the base direction
+func refreshDirection(e Element) {
+  if !e.Visible() {
+    return
+  }
+
+  if t, ok := e.(*Text); ok {
+    t.Show()
+  } else if inp, ok := e.(*Input); ok {
+    inp.sync()
+  }
+
+  for _, child := range e.Children() {
+    refreshDirection(child)
+  }
+}
@@ -68,9 +68,10 @@ type DrawPass1Data struct {
   Skin *SkinMap
 
 
-  imageTris  []uint32 // so we don't need to search Type for VTYPE_IMAGE
-  images     []*ImageData // same pointers are assumed to be same images
-  imageInfos map[*ImageData]ImageInfo
+  imageTris   []uint32 // so we don't need to search Type for VTYPE_IMAGE
+  images      []*ImageData // same pointers are assumed to be same images
+  imageSynced []bool // texture origin already added to tcoords, tris that weren't repositioned mustn't be translated again
+  imageInfos  map[*ImageData]ImageInfo
 }
 
 type DrawPass2Data struct {
@@ -143,6 +144,7 @@ func newDrawPass1Data(skin *SkinMap) *DrawPass1Data {
     skin, 
     make([]uint32, 0),
     make([]*ImageData, 0),
+    make([]bool, 0),
     make(map[*ImageData]ImageInfo),
   }
 
@@ -740,6 +742,7 @@ func (d *DrawPass1Data) SetQuadImage(tri0, tri1 uint32, img *ImageData) {
   if tri0Index == -1 {
     d.imageTris = append(d.imageTris, tri0)
     d.images = append(d.images, img)
+    d.imageSynced = append(d.imageSynced, false)
   } else {
     d.images[tri0Index] = img
   }
@@ -747,6 +750,7 @@ func (d *DrawPass1Data) SetQuadImage(tri0, tri1 uint32, img *ImageData) {
   if tri1Index == -1 {
     d.imageTris = append(d.imageTris, tri1)
     d.images = append(d.images, img)
+    d.imageSynced = append(d.imageSynced, false)
   } else {
     d.images[tri1Index] = img
   }
@@ -754,6 +758,16 @@ func (d *DrawPass1Data) SetQuadImage(tri0, tri1 uint32, img *ImageData) {
   d.setQuadImageRelTCoords(tri0, tri1, img.W, img.H)
 }
 
+func (d *DrawPass1Data) setQuadImageRelTCoords(tri0, tri1 uint32, w_, h_ int) {
+  d.DrawPassData.setQuadImageRelTCoords(tri0, tri1, w_, h_)
+
+  for i, tri := range d.imageTris {
+    if tri == tri0 || tri == tri1 {
+      d.imageSynced[i] = false
+    }
+  }
+}
+
 func (d *DrawPassData) setQuadImageRelTCoords(tri0, tri1 uint32, w_, h_ int) {
   w := float32(w_)/float32(d.texWidth)
   h := float32(h_)/float32(d.texHeight)
@@ -812,6 +826,26 @@ func (d *DrawPassData) SetQuadColorLinearVGrad(tri0 uint32, tri1 uint32, cTop, c
   d.Color.Set4(tri1, 2, rB, gB, bB, aB)
 }
 
+func (d *DrawPassData) SetQuadColorLinearHGrad(tri0 uint32, tri1 uint32, cLeft, cRight sdl.Color) {
+  rL := float32(cLeft.R)/float32(256)
+  gL := float32(cLeft.G)/float32(256)
+  bL := float32(cLeft.B)/float32(256)
+  aL := float32(cLeft.A)/float32(256)
+
+  rR := float32(cRight.R)/float32(256)
+  gR := float32(cRight.G)/float32(256)
+  bR := float32(cRight.B)/float32(256)
+  aR := float32(cRight.A)/float32(256)
+
+  d.Color.Set4(tri0, 0, rL, gL, bL, aL)
+  d.Color.Set4(tri0, 1, rR, gR, bR, aR)
+  d.Color.Set4(tri0, 2, rL, gL, bL, aL)
+
+  d.Color.Set4(tri1, 0, rR, gR, bR, aR)
+  d.Color.Set4(tri1, 1, rR, gR, bR, aR)
+  d.Color.Set4(tri1, 2, rL, gL, bL, aL)
+}
+
 func (d *DrawPass2Data) SetGlyphCoord(triId uint32, vertexId uint32, x_ int, y_ int) {
   x := float32(x_)/float32(d.Glyphs.size)
   y := float32(y_)/float32(d.Glyphs.size)
@@ -888,23 +922,10 @@ func (d *DrawPass2Data) SyncAndBind() {
   d.Glyphs.bind()
 }
 
-// XXX: should we make a distinction between posDirty and dirty?
 func (d *DrawPassData) dirty() bool {
   return d.Pos.dirty || d.Type.dirty || d.Param.dirty || d.TCoord.dirty || d.Color.dirty
 }
 
-func (d *DrawPassData) posDirty() bool {
-  return d.Type.dirty // a change of type indicates that some elements became visible/hidden, and thus affect positioning of siblings etc.
-}
-
-func (d *DrawPassData) forcePosDirty() {
-  d.Type.dirty = true
-}
-
-func (d *DrawPassData) clearPosDirty() {
-  d.Type.dirty = false
-}
-
 func (d *DrawPass1Data) showBorderedElement(tris []uint32) {
   for i := 0; i < 3; i++ {
     for j := 0; j < 3; j++ {
@@ -1084,7 +1105,7 @@ func (d *DrawPass1Data) SyncImagesToTexture() {
     img := d.images[i]
 
     info, ok := d.imageInfos[img]
-    if ok && info.Used {
+    if ok && info.Used && !d.imageSynced[i] {
       if info.X < 0 || info.Y < 0 {
         info.X, info.Y = d.Skin.AllocImage(img, d.imageInfos) // at this point the unused images can be deallocated if needed
 
@@ -1097,6 +1118,8 @@ func (d *DrawPass1Data) SyncImagesToTexture() {
       d.translateTCoord(tri, 0, float32(info.X), float32(info.Y))
       d.translateTCoord(tri, 1, float32(info.X), float32(info.Y))
       d.translateTCoord(tri, 2, float32(info.X), float32(info.Y))
+
+      d.imageSynced[i] = true
     }
   }
 
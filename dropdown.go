@@ -9,15 +9,6 @@ import (
 func NewDropdown(align Align, items []MenuItemConfig) *Button {
   e := NewStickyFlatButton()
 
-  menuItemMaker := func() []*MenuItem {
-    items_ := make([]*MenuItem, len(items))
-    for i, item := range items {
-      items_[i] = newMenuItemFromConfig(item)
-    }
-
-    return items_
-  }
-
   m := e.Root.Menu
 
   e.OnClick(func() {
@@ -30,23 +21,14 @@ func NewDropdown(align Align, items []MenuItemConfig) *Button {
 
       m.ClearChildren()
 
-      items := menuItemMaker()
-
-      menuW := 0
-      for _, item := range items {
+      for _, item := range m.AddConfigItems(items, bh) {
         item.On("click", func(evt *Event) {
           e.Unstick()
         })
-
-        m.AddItem(item.H(bh), true, false)
-
-        itemW, _ := item.GetSize()
-
-        if itemW > menuW {
-          menuW = itemW
-        }
       }
 
+      menuW := m.ChildrenWidth()
+
       switch align {
       case START:
         m.ShowAt(
@@ -36,6 +36,10 @@ type Element interface {
   IsFocusable() bool // implemented by ElementData in general case (i.e. hasEvent(e, "focus) && e.Visible())
   
   Crop(r Rect)
+
+  layoutState() *layoutState // implemented by ElementData
+  dependencies() []Element // implemented by ElementData, returns closerThan
+  elementData() *ElementData // implemented by ElementData, used by the document loader to set the basic positioning settings
 }
 
 type Container interface {
@@ -28,6 +28,8 @@ type ElementData struct {
   visible bool
   enabled bool
   deleted bool
+
+  layout layoutState
 }
 
 func newElementData(frame *Frame, nInitTris1 int, nInitTris2 int) ElementData {
@@ -49,6 +51,7 @@ func newElementData(frame *Frame, nInitTris1 int, nInitTris2 int) ElementData {
     make(map[string]EventListener),
     0, 0, [4]int{0, 0, 0, 0}, 0,
     Rect{0, 0, 0, 0}, -1, true, true, false,
+    newLayoutState(),
   }
 }
 func NewElementData(nInitTris1 int, nInitTris2 int) ElementData {
@@ -98,6 +101,8 @@ func (e *ElementData) GetSize() (int, int) {
 }
 
 func (e *ElementData) Hide() {
+  e.Root.ForceElementPosDirty(e.parent)
+
   for _, tri := range e.p1Tris {
     e.Root.P1.SetTriType(tri, VTYPE_HIDDEN)
   }
@@ -114,6 +119,8 @@ func (e *ElementData) Hide() {
 }
 
 func (e *ElementData) Show() {
+  e.Root.ForceElementPosDirty(e.parent)
+
   for _, child := range e.children {
     child.Show()
   }
@@ -217,6 +224,8 @@ func (e *ElementData) Translate(dx, dy int) {
   }
 
   e.rect = e.rect.Translate(dx, dy)
+
+  e.layout.translate(dx, dy)
 }
 
 // default positioning of children
@@ -227,7 +236,7 @@ func (e *ElementData) CalcPosChildren(maxWidth, maxHeight, maxZIndex int) (int,
   maxW := 0
   for _, child := range e.children {
     if child.Visible() {
-      w, dy := child.CalcPos(maxWidth - e.padding[1] - e.padding[3], maxHeight - y - e.padding[2], maxZIndex)
+      w, dy := calcPos(child, maxWidth - e.padding[1] - e.padding[3], maxHeight - y - e.padding[2], maxZIndex)
 
       child.Translate(e.padding[3], y)
 
@@ -256,13 +265,13 @@ func (e *ElementData) Delete() {
   e.Root.P1.Dealloc(e.p1Tris)
   e.Root.P2.Dealloc(e.p2Tris)
 
+  e.Root.ForceElementDepthDirty(e.parent)
+
   e.deleted = true
   e.p1Tris = nil
   e.p2Tris = nil
   e.parent = nil
 
-  e.Root.ForcePosDirty()
-
   e.Root = nil // so error is thrown if deletion is tried again
 }
 
@@ -270,6 +279,18 @@ func (e *ElementData) Deleted() bool {
   return e.deleted
 }
 
+func (e *ElementData) layoutState() *layoutState {
+  return &e.layout
+}
+
+func (e *ElementData) dependencies() []Element {
+  return e.closerThan
+}
+
+func (e *ElementData) elementData() *ElementData {
+  return e
+}
+
 func (e *ElementData) ClearChildren() {
   for _, child := range e.children {
     child.Delete()
@@ -321,6 +342,8 @@ func (e *ElementData) IsFocusable() bool {
 func (e *ElementData) Crop(r Rect) {
   e.rect = e.rect.Common(r)
 
+  e.layout.addCrop(r)
+
   for _, tri := range e.p1Tris {
     e.Root.P1.CropTri(tri, r)
   }
@@ -7,6 +7,8 @@ type ElementStack struct {
   offset int
 
   dirty bool
+
+  root Element // if set: only the subtree of root is being recalculated
 }
 
 func newElementStack() *ElementStack {
@@ -14,10 +16,16 @@ func newElementStack() *ElementStack {
     make([]Element, 0),
     1, // start at 1, because 0 isn't visible
     true,
+    nil,
   }
 }
 
 func (s *ElementStack) contains(dep Element) bool {
+  // elements outside the recalculated subtree keep their lower z-index
+  if s.root != nil && !hasAncestor(dep, s.root) {
+    return true
+  }
+
   for _, e := range s.stack {
     if e == dep {
       return true
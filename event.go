@@ -22,19 +22,24 @@ type Event struct {
 
   Value string // for text Input
   AppMsg string // for quit
+  Row   int    // model row, for the table events
+  Tab   int    // tab index, for the "tabclose" event
 
   stopBubblingElement Element // exclusive
   stopBubbling bool
 
   stopPropagation bool // multiple functions can be tied to a single eventlisteners, this stops older functions from being called
+  canceled bool // for the events that announce an action, e.g. "tabclose"
   callback  func(args ...interface{}) // for async quit
 }
 
 func currentMousePos() (int, int) {
   x_, y_, _ := sdl.GetMouseState()
 
-  x := int(x_)
-  y := int(y_)
+  app := getApp()
+
+  x := app.toLogical(int(x_))
+  y := app.toLogical(int(y_))
 
   return x, y
 }
@@ -49,7 +54,7 @@ func NewMouseEvent(x, y int) *Event {
   shift := ks[sdl.SCANCODE_LSHIFT] > 0 || ks[sdl.SCANCODE_RSHIFT] > 0
   alt := ks[sdl.SCANCODE_LALT] > 0 || ks[sdl.SCANCODE_RALT] > 0
 
-  return &Event{x, y, 0, 0, "", ctrl, shift, alt, "", "", nil, false, false, nil}
+  return &Event{x, y, 0, 0, "", ctrl, shift, alt, "", "", 0, 0, nil, false, false, false, nil}
 }
 
 func NewMouseMoveEvent(x, y int, dx, dy int) *Event {
@@ -69,15 +74,15 @@ func NewMouseWheelEvent(dx, dy int) *Event {
 }
 
 func NewKeyboardEvent(keyName string, ctrl bool, shift bool, alt bool) *Event {
-  return &Event{0, 0, 0, 0, keyName, ctrl, shift, alt, "", "", nil, false, false, nil}
+  return &Event{0, 0, 0, 0, keyName, ctrl, shift, alt, "", "", 0, 0, nil, false, false, false, nil}
 }
 
 func NewTextInputEvent(str string) *Event {
-  return &Event{0, 0, 0, 0, "", false, false, false, str, "", nil, false, false, nil}
+  return &Event{0, 0, 0, 0, "", false, false, false, str, "", 0, 0, nil, false, false, false, nil}
 }
 
 func NewAppEvent(msg string, fn func(args ...interface{})) *Event {
-  return &Event{0, 0, 0, 0, "", false, false, false, "", msg, nil, false, false, fn}
+  return &Event{0, 0, 0, 0, "", false, false, false, "", msg, 0, 0, nil, false, false, false, fn}
 }
 
 func (e *Event) StopBubbling() {
@@ -88,6 +93,15 @@ func (e *Event) StopPropagation() {
   e.stopPropagation = true
 }
 
+// the announced action isn't performed
+func (e *Event) Cancel() {
+  e.canceled = true
+}
+
+func (e *Event) Canceled() bool {
+  return e.canceled
+}
+
 func (e *Event) Callback(args ...interface{}) {
   e.callback(args...)
 }
@@ -189,6 +203,9 @@ func extractKeyboardEventDetails(event *sdl.KeyboardEvent) (string, string, bool
   case sdl.K_PAGEUP:
     kType = "pageup"
     break
+  case sdl.K_F2:
+    kType = "f2"
+    break
   }
 
   shift := (event.Keysym.Mod & sdl.KMOD_SHIFT > 0)
@@ -197,3 +214,16 @@ func extractKeyboardEventDetails(event *sdl.KeyboardEvent) (string, string, bool
 
   return eType, kType, ctrl, shift, alt
 }
+
+// Ctrl+= is included because plus requires shift on most keyboard layouts
+func isZoomInKey(key sdl.Keycode) bool {
+  return key == sdl.K_PLUS || key == sdl.K_EQUALS || key == sdl.K_KP_PLUS
+}
+
+func isZoomOutKey(key sdl.Keycode) bool {
+  return key == sdl.K_MINUS || key == sdl.K_KP_MINUS
+}
+
+func isZoomResetKey(key sdl.Keycode) bool {
+  return key == sdl.K_0 || key == sdl.K_KP_0
+}
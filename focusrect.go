@@ -43,6 +43,8 @@ func (e *FocusRect) setTypesAndTCoords() {
 func (e *FocusRect) Show(anchor Element) {
   e.anchor = anchor
 
+  e.Root.forceOverlaysPosDirty()
+
   for i, tri := range e.tris {
     if i < 8 || i > 9 {
       e.Root.P1.SetTriType(tri, VTYPE_SKIN)
@@ -53,6 +55,8 @@ func (e *FocusRect) Show(anchor Element) {
 func (e *FocusRect) Hide() {
   e.anchor = nil
 
+  e.Root.forceOverlaysPosDirty()
+
   for _, tri := range e.tris {
     e.Root.P1.SetTriType(tri, VTYPE_HIDDEN)
   }
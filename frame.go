@@ -18,7 +18,20 @@ type Frame struct {
   Menu      *Menu
   FocusRect *FocusRect
 
+  subMenus []*Menu   // menu levels after Menu, created when needed
+  menuBar  *MenuBar  // activated by the alt key
+
+  debugOverlay *debugOverlay
+
   state     *FrameState
+
+  // layout state
+  allPosDirty      bool
+  overlaysPosDirty bool // menu or focusrect changed
+  dirtyElements    []Element
+  bodyTop          int // next free z-index for body elements
+  menuOffset       int // first z-index of menu elements
+  depthOverflow    bool // elements added during layout ran out of z-indices
 }
 
 // skinmap and glyphmap can be shared across multiple windows/frames/layers
@@ -26,13 +39,16 @@ func newFrame(isFirst bool, skin *SkinMap, glyphs *GlyphMap) *Frame {
   frame := &Frame{
     0, 0, 0, 0, 0,
     newDrawPass1Data(skin), newDrawPass2Data(glyphs),
-    nil, nil, nil, newFrameState(),
+    nil, nil, nil, nil, nil, nil, newFrameState(),
+    true, false, make([]Element, 0), 0, 0, false,
   }
 
   frame.Body      = newBody(frame, isFirst)
-  frame.Menu      = newMenu(frame)
+  frame.Menu      = newMenu(frame, 0)
   frame.FocusRect = newFocusRect(frame)
 
+  frame.debugOverlay = newDebugOverlay(frame)
+
   return frame
 }
 
@@ -50,17 +66,37 @@ func (e *Frame) dirty() bool {
 }
 
 func (e *Frame) posDirty() bool {
-  return e.P1.posDirty() || e.P2.posDirty()
+  return e.allPosDirty || e.overlaysPosDirty || len(e.dirtyElements) > 0
 }
 
-func (e *Frame) clearPosDirty() {
-  e.P1.clearPosDirty()
-  e.P2.clearPosDirty()
+// recalculate the depth and position of all elements during the next draw
+// use ForceElementPosDirty() if only a single element changed
+func (e *Frame) ForcePosDirty() {
+  e.allPosDirty = true
 }
 
-func (e *Frame) ForcePosDirty() {
-  e.P1.forcePosDirty()
-  e.P2.forcePosDirty()
+func (e *Frame) forceOverlaysPosDirty() {
+  e.overlaysPosDirty = true
+}
+
+// depth and position of only the dirty subtrees are recalculated if possible
+func (e *Frame) calcLayout() {
+  if !e.allPosDirty && !e.calcDirtyDepth() {
+    e.allPosDirty = true
+  }
+
+  if e.allPosDirty {
+    e.CalcDepth()
+    e.CalcPos()
+  } else {
+    e.calcDirtyPos()
+  }
+
+  // a full recalculation is done during the next draw if needed
+  e.allPosDirty = e.depthOverflow
+  e.depthOverflow = false
+  e.overlaysPosDirty = false
+  e.clearDirtyElements()
 }
 
 // needed when switching frames
@@ -112,17 +148,18 @@ func (e *Frame) CalcDepth() {
 
   stack.dirty = true
 
+  e.bodyTop = stack.maxZIndex()
+
   // add some offset for menu, so it definitely lies above all body elements
-  // XXX: why?
+  // the offset also leaves room for body elements that are added later
   stack.offset = 2*stack.maxZIndex()
 
-  for ; stack.dirty; {
-    stack.dirty = false
+  e.menuOffset = stack.maxZIndex()
 
-    e.Menu.CalcDepth(stack)
-  }
+  e.calcMenusDepth(stack)
 
-  e.maxZIndex = stack.maxZIndex()
+  // leave room for menus that are filled later
+  e.maxZIndex = 2*stack.maxZIndex()
 }
 
 func (e *Frame) CalcPos() {
@@ -133,36 +170,54 @@ func (e *Frame) CalcPos() {
   x, y := e.GetPos()
   w, h := e.GetSize()
 
-  e.Body.CalcPos(w, h, e.maxZIndex)
-
-  e.Menu.CalcPos(w, h, e.maxZIndex)
-
-  e.FocusRect.CalcPos(w, h, e.maxZIndex)
+  calcPos(e.Body, w, h, e.maxZIndex)
 
   if x != 0 || y != 0 {
     e.Body.Translate(x, y)
-    e.Menu.Translate(x, y)
-    e.FocusRect.Translate(x, y)
   }
 
+  e.calcOverlaysPos()
+
   e.P1.SyncImagesToTexture()
 }
 
+// Menu and FocusRect depend on the rects of their anchors, so are always recalculated
+// the debug overlay depends on all rects
+func (e *Frame) calcOverlaysPos() {
+  x, y := e.GetPos()
+  w, h := e.GetSize()
+
+  // each submenu depends on the position of the previous level
+  for _, menu := range e.menus() {
+    menu.CalcPos(w, h, e.maxZIndex)
+
+    if x != 0 || y != 0 {
+      menu.Translate(x, y)
+    }
+  }
+
+  e.FocusRect.CalcPos(w, h, e.maxZIndex)
+
+  e.debugOverlay.CalcPos(w, h, e.maxZIndex)
+}
+
 func (e *Frame) Animate(tick uint64) {
   e.Body.Animate(tick)
 
-  e.Menu.Animate(tick)
+  for _, menu := range e.menus() {
+    menu.Animate(tick)
+  }
 
   e.FocusRect.Animate(tick)
 }
 
 // if this function returns `false` incorrectly, then oldMouseElement probably doesnt correctly have Body as ancestor
 func (e *Frame) findMouseElement(oldMouseElement Element, x, y int) (Element, bool) {
-  if e.Menu.IsHit(x, y) {
+  if menu := e.menuAt(x, y); menu != nil {
     if oldMouseElement == nil {
-      oldMouseElement = e.Menu
-    } else if !hasAncestor(oldMouseElement, e.Menu) {
-      oldMouseElement = e.Menu
+      oldMouseElement = menu
+    } else if !hasAncestor(oldMouseElement, menu) {
+      oldMouseElement = menu
 
       newMouseElement, _ := findHitElement(oldMouseElement, x, y)
 
@@ -187,7 +242,76 @@ func (e *Frame) findMouseElement(oldMouseElement Element, x, y int) (Element, bo
 
 func (e *Frame) Clear() {
   e.Body.ClearChildren()
-  e.Menu.ClearChildren()
+
+  for _, menu := range e.menus() {
+    menu.ClearChildren()
+  }
+}
+
+// all menu levels, starting with Menu
+func (e *Frame) menus() []*Menu {
+  return append([]*Menu{e.Menu}, e.subMenus...)
+}
+
+func (e *Frame) menuLevel(level int) *Menu {
+  if level == 0 {
+    return e.Menu
+  }
+
+  for len(e.subMenus) < level {
+    e.subMenus = append(e.subMenus, newMenu(e, len(e.subMenus) + 1))
+
+    // the new menu needs a z-index
+    e.ForcePosDirty()
+  }
+
+  return e.subMenus[level - 1]
+}
+
+// deepest visible menu level, nil if no menu is visible
+func (e *Frame) openMenu() *Menu {
+  var res *Menu = nil
+
+  for _, menu := range e.menus() {
+    if menu.Visible() {
+      res = menu
+    }
+  }
+
+  return res
+}
+
+// deepest menu level that is hit, nil if none
+func (e *Frame) menuAt(x, y int) *Menu {
+  var res *Menu = nil
+
+  for _, menu := range e.menus() {
+    if menu.IsHit(x, y) {
+      res = menu
+    }
+  }
+
+  return res
+}
+
+func (e *Frame) isMenu(el Element) bool {
+  for _, menu := range e.menus() {
+    if el == menu {
+      return true
+    }
+  }
+
+  return false
+}
+
+func (e *Frame) inMenu(el Element) bool {
+  for _, menu := range e.menus() {
+    if hasAncestor(el, menu) {
+      return true
+    }
+  }
+
+  return false
 }
 
 func (e *Frame) CurrentTick() uint64 {
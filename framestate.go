@@ -16,6 +16,8 @@ type FrameState struct {
   lastTick       uint64
   lastUpTick     uint64
   blockNextMouseButtonEvent bool
+  altAlone       bool // alt is pressed without any other key, which activates the menu bar when released
+  lastClicked    Element // unlike lastDown this isn't cleared on mouseup
 }
 
 func newFrameState() *FrameState {
@@ -30,5 +32,7 @@ func newFrameState() *FrameState {
     0,
     0,0,
     false,
+    false,
+    nil,
   }
 }
@@ -32,20 +32,28 @@ func NewHor(hAlign, vAlign Align, spacing int) *Hor {
 
 // z is irrelevant here
 func (e *Hor) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  // rtl: children are placed in reverse order, and the alignment is mirrored
+  children := e.children
+  hAlign := e.hAlign
+  if isRTL() {
+    children = reverseElements(e.children)
+    hAlign = mirrorAlign(hAlign)
+  }
+
   // first space the children inline
 
   x := e.padding[3]
   maxChildH := 0
 
-  childHs := make([]int, len(e.children))
-  childWs := make([]int, len(e.children))
+  childHs := make([]int, len(children))
+  childWs := make([]int, len(children))
 
-  for i, child := range e.children {
+  for i, child := range children {
     if i > 0 {
       x += e.spacing
     }
 
-    childW, childH := child.CalcPos(
+    childW, childH := calcPos(child,
       maxWidth - x - e.padding[1], 
       maxHeight - e.padding[0] - e.padding[2], 
       maxZIndex)
@@ -63,27 +71,27 @@ func (e *Hor) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   }
 
   // dx[0] is 0 for STRETCH, dx[0..end] is a general translation for CENTER and END, dx[0..end] is 0 for START
-  dx := make([]int, len(e.children)) 
+  dx := make([]int, len(children)) 
   someDXSet := false
   if x < (maxWidth - e.padding[1]) {
-    switch e.hAlign {
+    switch hAlign {
     case CENTER:
       dxAll := (maxWidth - x - e.padding[1])/2
-      for i, _ := range e.children {
+      for i, _ := range children {
         dx[i] = dxAll
       }
       someDXSet = true
     case END:
       dxAll := maxWidth - x - e.padding[1]
-      for i, _ := range e.children {
+      for i, _ := range children {
         dx[i] = dxAll
       }
       someDXSet = true
     case STRETCH:
       rem := maxWidth - x - e.padding[1]
-      remPerChild := float64(rem)/float64(len(e.children) - 1)
+      remPerChild := float64(rem)/float64(len(children) - 1)
 
-      for i, _ := range e.children {
+      for i, _ := range children {
         dx[i] = int(math.Floor(float64(i)*remPerChild))
       }
       someDXSet = true
@@ -98,7 +106,7 @@ func (e *Hor) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   }
 
   if someDXSet || e.vAlign != START {
-    for i, child := range e.children {
+    for i, child := range children {
       dy := 0
 
       switch e.vAlign {
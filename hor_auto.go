@@ -5,6 +5,7 @@ func (e *Hor) A(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -32,19 +33,19 @@ func (e *Hor) Padding(p ...int) *Hor {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Hor) Spacing(s int) *Hor {
   e.spacing = s
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Hor) H(h int) *Hor {
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -54,6 +54,13 @@ func (e *Icon) SetOrientation(or Orientation) *Icon {
   return e
 }
 
+func (e *Icon) SetColor(c sdl.Color) {
+  e.mainColor = c
+
+  e.Root.P2.SetColorConst(e.p2Tris[2], c)
+  e.Root.P2.SetColorConst(e.p2Tris[3], c)
+}
+
 func (e *Icon) ChangeGlyph(name string) {
   e.name = name
   e.glyph = e.Root.P2.Glyphs.GetGlyph(name)
@@ -106,6 +113,8 @@ func (e *Icon) Show() {
   for _, tri := range e.p2Tris {
     e.Root.P2.Param.Set1Const(tri, float32(scale))
   }
+
+  e.ElementData.Show()
 }
 
 func (e *Icon) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
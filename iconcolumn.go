@@ -0,0 +1,68 @@
+package glui
+
+import (
+  "fmt"
+)
+
+//go:generate ./gen_element IconColumn "appendChild CalcDepth"
+
+// values are glyph names of the GlyphMap
+type IconColumn struct {
+  BasicColumn
+
+  size int
+}
+
+func NewIconColumn(caption string, size int) *IconColumn {
+  e_ := NewBasicColumn(CENTER)
+
+  e := &IconColumn{
+    *e_,
+    size,
+  }
+
+  e.self = e
+  e.head, e.caption, e.arrow, e.prio = newHeadButton(caption)
+  e.head.OnClick(e.onClickSort)
+
+  return e
+}
+
+func (e *IconColumn) Format(x interface{}) string {
+  if s, ok := x.(string); ok {
+    return s
+  } else {
+    return fmt.Sprint(x)
+  }
+}
+
+func (e *IconColumn) Less(a, b interface{}) bool {
+  return e.Format(a) < e.Format(b)
+}
+
+func (e *IconColumn) Parse(s string) (interface{}, error) {
+  return s, nil
+}
+
+// the glyph is changed by setCell
+func (e *IconColumn) newCell() Element {
+  return NewIcon("arrow-down-drop", e.size)
+}
+
+func (e *IconColumn) setCell(cell Element, x interface{}, selected bool) {
+  icon := cell.(*Icon)
+
+  if name := e.Format(x); name != icon.name {
+    icon.ChangeGlyph(name)
+  }
+
+  if selected {
+    icon.SetColor(WHITE)
+  } else {
+    icon.SetColor(BLACK)
+  }
+}
+
+func (e *IconColumn) onClickSort() {
+  e.table.clickSort(e)
+}
@@ -4,13 +4,15 @@ import (
   "fmt"
   "math"
   "os"
-  "strings"
+  "unicode/utf8"
 
   "github.com/veandco/go-sdl2/sdl"
 )
 
 //go:generate ./gen_element Input "On Padding"
 
+var INPUT_INVALID_COLOR = sdl.Color{0xff, 0xd8, 0xd8, 0xff}
+
 // overflow not (yet) allowed
 type Input struct {
   ElementData
@@ -20,13 +22,14 @@ type Input struct {
   // state
   text        *Text 
   selText     *Text
-  value       string // not necessarily the same as text (in case of overflow)
-  col0        int // defaults to end of string
+  value       string // not necessarily the same as text (in case of overflow or bidi text)
+  col0        int // defaults to end of string, in runes
   col1        int // end of selection, same as col0 for no selection
   mouseDown   bool
   currentVBar bool
   lastTick    uint64
   vBarTick    uint64
+  invalid     bool
 }
 
 func NewInput() *Input {
@@ -41,12 +44,17 @@ func NewInput() *Input {
     false, 
     0, 
     0,
+    false,
   }
 
   e.width, e.height = 400, 50
 
   e.selText.SetColor(sdl.Color{0xff, 0xff, 0xff, 0xff})
 
+  // sync() does the bidi reordering, because the selection must be applied to the visual order
+  e.text.bidi = false
+  e.selText.bidi = false
+
   // default is 1px of right padding (to accomodate the vBar)
   e.padding[1] = 1
 
@@ -85,18 +93,18 @@ func (e *Input) onKeyPress(evt *Event) {
 
   switch {
   case evt.Key == "backspace":
-    n := len(e.value)
+    n := e.valueLen()
     if n > 0 {
       if e.hasSel() {
         e.delSel()
       } else if e.atEnd() {
         col := moveInputCol(e.value, e.col0, false, evt.Ctrl)
-        e.value = e.value[0:col]
+        e.value = e.substr(0, col)
         e.col0 = col
         e.col1 = col
       } else if e.col0 > 0 {
         col := moveInputCol(e.value, e.col0, false, evt.Ctrl)
-        e.value = e.value[0:col] + e.value[e.col0:]
+        e.value = e.substr(0, col) + e.substr(e.col0, n)
         e.col0 = col
         e.col1 = col
       }
@@ -117,19 +125,19 @@ func (e *Input) onKeyPress(evt *Event) {
     e.refreshVBar()
     break
   case evt.Key == "delete":
-    n := len(e.value) 
+    n := e.valueLen()
     if n > 0 && !e.atEnd() {
       if e.hasSel() {
         e.delSel()
       } else {
         col := moveInputCol(e.value, e.col0, true, evt.Ctrl)
-        e.value = e.value[0:e.col0] + e.value[col:]
+        e.value = e.substr(0, e.col0) + e.substr(col, n)
       }
     }
     e.refreshVBar()
     break
   case evt.Key == "left":
-    col := moveInputCol(e.value, e.col1, false, evt.Ctrl)
+    col := e.moveCaret(e.col1, false, evt.Ctrl)
     if evt.Shift {
       e.col1 = col
     } else {
@@ -145,7 +153,7 @@ func (e *Input) onKeyPress(evt *Event) {
     e.refreshVBar()
     break
   case evt.Key == "right":
-    col := moveInputCol(e.value, e.col1, true, evt.Ctrl)
+    col := e.moveCaret(e.col1, true, evt.Ctrl)
     if evt.Shift {
       e.col1 = col
     } else {
@@ -171,9 +179,9 @@ func (e *Input) onKeyPress(evt *Event) {
     break
   case evt.Key == "end":
     if evt.Shift {
-      e.col1 = len(e.value)
+      e.col1 = e.valueLen()
     } else {
-      e.col0 = len(e.value)
+      e.col0 = e.valueLen()
       e.col1 = e.col0
     }
     e.refreshVBar()
@@ -183,9 +191,35 @@ func (e *Input) onKeyPress(evt *Event) {
   e.sync()
 }
 
+func (e *Input) Value() string {
+  return e.value
+}
+
+// the caret is placed at the end
+func (e *Input) SetValue(v string) {
+  e.value = v
+  e.col0 = e.valueLen()
+  e.col1 = e.col0
+
+  e.sync()
+}
+
 func (e *Input) selAll() {
   e.col0 = 0
-  e.col1 = len(e.value)
+  e.col1 = e.valueLen()
+}
+
+func (e *Input) runes() []rune {
+  return []rune(e.value)
+}
+
+func (e *Input) valueLen() int {
+  return utf8.RuneCountInString(e.value)
+}
+
+// a and b are rune indices
+func (e *Input) substr(a, b int) string {
+  return string(e.runes()[a:b])
 }
 
 func (e *Input) onDoubleClick(evt *Event) {
@@ -198,7 +232,8 @@ func (e *Input) onDoubleClick(evt *Event) {
   e.col0 = col0
   e.col1 = col1
 
-  for ; e.col1 > 0 && isDelimiter(rune(e.value[e.col1-1])); {
+  runes := e.runes()
+  for ; e.col1 > 0 && isDelimiter(runes[e.col1-1]); {
     e.col1-=1
   }
 
@@ -255,12 +290,12 @@ func (e *Input) mousePosToCol(evt *Event) int {
     colFromRight = 0.0
   }
 
-  col := len(e.value) - int(colFromRight)
-  if col < 0 {
-    col = 0
+  v := e.valueLen() - int(colFromRight)
+  if v < 0 {
+    v = 0
   } 
 
-  return col
+  return e.visualToCol(v)
 }
 
 func (e *Input) onMouseDown(evt *Event) {
@@ -277,34 +312,35 @@ func (e *Input) onMouseDown(evt *Event) {
   e.sync()
 }
 
+// in logical order
 func moveInputCol(value string, col int, moveRight bool, word bool) int {
+  runes := []rune(value)
+  n := len(runes)
+
   if word {
     // move by word
     if moveRight {
-      if col >= len(value) {
-        return len(value)
+      if col >= n {
+        return n
       } else {
-        for i, c := range value[col:] {
-          if i > 0 {
-            prev := value[col+i-1]
-            if isDelimiter(rune(prev)) && !isDelimiter(rune(c)) {
-              return col+i
-            }
+        for i := col + 1; i < n; i++ {
+          if isDelimiter(runes[i-1]) && !isDelimiter(runes[i]) {
+            return i
           }
         }
       }
 
-      return len(value)
+      return n
     } else {
       if col <= 0 {
         return 0
       } 
 
       for i := col - 1; i > 0; i-- {
-        c := value[i]
-        prev := value[i-1]
+        c := runes[i]
+        prev := runes[i-1]
 
-        if isDelimiter(rune(prev)) && !isDelimiter(rune(c)) {
+        if isDelimiter(prev) && !isDelimiter(c) {
           return i
         }
       }
@@ -313,8 +349,8 @@ func moveInputCol(value string, col int, moveRight bool, word bool) int {
     }
   } else {
     if moveRight {
-      if col+1 >= len(value) {
-        return len(value)
+      if col+1 >= n {
+        return n
       } else {
         return col + 1
       }
@@ -328,8 +364,113 @@ func moveInputCol(value string, col int, moveRight bool, word bool) int {
   }
 }
 
+// arrow keys move the caret in visual order, also when moving by word
+func (e *Input) moveCaret(col int, moveRight bool, word bool) int {
+  n := e.valueLen()
+  v := e.colToVisual(col)
+
+  // word boundaries are searched in the runes in visual order
+  visualValue := ""
+  if word {
+    runes := e.runes()
+    _, order := e.bidiInfo()
+
+    visualRunes := make([]rune, len(order))
+    for i, j := range order {
+      visualRunes[i] = runes[j]
+    }
+
+    visualValue = string(visualRunes)
+  }
+
+  for {
+    if word {
+      prev := v
+      v = moveInputCol(visualValue, v, moveRight, true)
+
+      if v == prev {
+        return col
+      }
+    } else if moveRight {
+      v++
+    } else {
+      v--
+    }
+
+    if v < 0 || v > n {
+      return col
+    }
+
+    // some visual positions map to the same logical position at direction boundaries
+    if c := e.visualToCol(v); c != col {
+      return c
+    }
+  }
+}
+
+func (e *Input) bidiInfo() ([]int, []int) {
+  levels := bidiLevels(e.runes(), isRTL())
+
+  return levels, bidiVisualOrder(levels)
+}
+
+// the caret lies on the leading edge of the rune at col, returns the number of visual cells to the left of the caret
+func (e *Input) colToVisual(col int) int {
+  levels, order := e.bidiInfo()
+
+  n := len(order)
+  if n == 0 {
+    return 0
+  }
+
+  visual := make([]int, n)
+  for v, i := range order {
+    visual[i] = v
+  }
+
+  if col < n {
+    if levels[col]%2 == 0 {
+      return visual[col]
+    } else {
+      return visual[col] + 1
+    }
+  } else {
+    if levels[n-1]%2 == 0 {
+      return visual[n-1] + 1
+    } else {
+      return visual[n-1]
+    }
+  }
+}
+
+// inverse of colToVisual()
+func (e *Input) visualToCol(v int) int {
+  levels, order := e.bidiInfo()
+
+  n := len(order)
+  if n == 0 {
+    return 0
+  }
+
+  if v < n {
+    i := order[v]
+    if levels[i]%2 == 0 {
+      return i
+    } else {
+      return i + 1
+    }
+  } else {
+    i := order[n-1]
+    if levels[i]%2 == 0 {
+      return i + 1
+    } else {
+      return i
+    }
+  }
+}
+
 func (e *Input) atEnd() bool {
-  return e.col0 == len(e.value)
+  return e.col0 == e.valueLen()
 }
 
 func (e *Input) maxLen() int {
@@ -338,10 +479,10 @@ func (e *Input) maxLen() int {
 
 func (e *Input) delSel() {
   col := e.selStart()
-  if e.selEnd() == len(e.value) {
-    e.value = e.value[0:col]
+  if e.selEnd() == e.valueLen() {
+    e.value = e.substr(0, col)
   } else {
-    e.value = e.value[0:col] + e.value[e.selEnd():]
+    e.value = e.substr(0, col) + e.substr(e.selEnd(), e.valueLen())
   }
   
   e.col0 = col
@@ -386,16 +527,16 @@ func (e *Input) onTextInput(evt *Event) {
 }
 
 func (e *Input) insertText(text string) {
-  if len(e.value) != e.maxLen() {
-    v := text
-    if len(e.value) + len(v) > e.maxLen() {
-      v = v[0:e.maxLen() - len(e.value)]
+  if e.valueLen() != e.maxLen() {
+    v := []rune(text)
+    if e.valueLen() + len(v) > e.maxLen() {
+      v = v[0:e.maxLen() - e.valueLen()]
     }
 
     if e.atEnd() {
-      e.value += v
+      e.value += string(v)
     } else {
-      e.value = e.value[0:e.col0] + v + e.value[e.col0:]
+      e.value = e.substr(0, e.col0) + string(v) + e.substr(e.col0, e.valueLen())
     }
 
     e.col0 += len(v)
@@ -458,9 +599,31 @@ func (e *Input) Show() {
 func (e *Input) setTypesAndTCoords() {
   e.Root.P1.setInputLikeElementTypesAndTCoords(e.p1Tris)
 
+  if e.invalid {
+    e.Root.P1.SetColorConst(e.p1Tris[8], INPUT_INVALID_COLOR)
+    e.Root.P1.SetColorConst(e.p1Tris[9], INPUT_INVALID_COLOR)
+  }
+
   e.setVBarTypeAndColor()
 }
 
+// invalid values get a red background
+func (e *Input) SetInvalid(b bool) {
+  e.invalid = b
+
+  c := e.Root.P1.Skin.InputBGColor()
+  if b {
+    c = INPUT_INVALID_COLOR
+  }
+
+  e.Root.P1.SetColorConst(e.p1Tris[8], c)
+  e.Root.P1.SetColorConst(e.p1Tris[9], c)
+}
+
+func (e *Input) Invalid() bool {
+  return e.invalid
+}
+
 func (e *Input) setVBarTypeAndColor() {
   tri0 := e.p1Tris[18]
   tri1 := e.p1Tris[19]
@@ -548,7 +711,7 @@ func (e *Input) hasSel() bool {
 }
 
 func (e *Input) getSelText() string {
-  return e.value[e.selStart():e.selEnd()]
+  return e.substr(e.selStart(), e.selEnd())
 }
 
 func (e *Input) calcVBarPos(maxZIndex int) {
@@ -556,9 +719,28 @@ func (e *Input) calcVBarPos(maxZIndex int) {
 
   y0 := e.height/2 - e.barHeight/2
 
+  // visual range of the caret or selection, a bidi selection isn't necessarily contiguous, so the whole range is highlighted
+  v0, v1 := e.colToVisual(e.col0), e.colToVisual(e.col0)
+  if e.hasSel() {
+    _, order := e.bidiInfo()
+
+    v0, v1 = len(order), 0
+    for v, i := range order {
+      if i >= e.selStart() && i < e.selEnd() {
+        if v < v0 {
+          v0 = v
+        }
+
+        if v + 1 > v1 {
+          v1 = v + 1
+        }
+      }
+    }
+  }
+
   // Right Aligned
   x0 := e.width - e.padding[1] - e.borderT() - 
-    int(math.Ceil(float64(len(e.value) - e.selStart())*e.text.RefAdvance()))
+    int(math.Ceil(float64(e.valueLen() - v0)*e.text.RefAdvance()))
 
   tri0 := e.p1Tris[18]
   tri1 := e.p1Tris[19]
@@ -572,7 +754,7 @@ func (e *Input) calcVBarPos(maxZIndex int) {
     e.Root.P1.SetColorConst(tri0, sdl.Color{0, 0, 0, 255})
     e.Root.P1.SetColorConst(tri1, sdl.Color{0, 0, 0, 255})
   } else {
-    vBarWidth = e.selWidth()*int(e.text.RefAdvance())
+    vBarWidth = (v1 - v0)*int(e.text.RefAdvance())
 
     // TODO: move this into show etc.
     e.Root.P1.SetColorConst(tri0, e.Root.P1.Skin.SelColor())
@@ -583,13 +765,27 @@ func (e *Input) calcVBarPos(maxZIndex int) {
 }
 
 func (e *Input) sync() {
-  if e.hasSel() && e.focused() {
-    e.text.SetContent(e.value[0:e.selStart()] + strings.Repeat(" ", e.selWidth()) + e.value[e.selEnd():])
-    e.selText.SetContent(strings.Repeat(" ", e.selStart()) + e.getSelText() + strings.Repeat(" ", len(e.value) - e.selEnd()))
-  } else {
-    e.text.SetContent(e.value)
-    e.selText.SetContent(strings.Repeat(" ", len(e.value)))
+  visual := bidiReorder(e.runes(), isRTL())
+  _, order := e.bidiInfo()
+
+  text := make([]rune, len(visual))
+  selText := make([]rune, len(visual))
+
+  for v, i := range order {
+    if e.hasSel() && e.focused() && i >= e.selStart() && i < e.selEnd() {
+      text[v] = ' '
+      selText[v] = visual[v]
+    } else {
+      text[v] = visual[v]
+      selText[v] = ' '
+    }
   }
+
+  e.text.SetContent(string(text))
+  e.selText.SetContent(string(selText))
+
+  // text and selText aren't children, so the input itself must be repositioned
+  e.Root.ForceElementPosDirty(e)
 }
 
 func (e *Input) CalcDepth(stack *ElementStack) {
@@ -617,7 +813,7 @@ func (e *Input) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   e.SetBorderedElementPos(w, h, e.borderT(), maxZIndex)
 
   for _, textElem := range []*Text{e.text, e.selText} {
-    textWidth, textHeight := textElem.CalcPos(w - e.padding[1] - e.padding[3] - 2*e.borderT(), 0, maxZIndex)
+    textWidth, textHeight := calcPos(textElem, w - e.padding[1] - e.padding[3] - 2*e.borderT(), 0, maxZIndex)
 
     // RIGHT ALIGN
     textElem.Translate(
@@ -26,7 +26,7 @@ func (e *Input) Padding(p ...int) *Input {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
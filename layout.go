@@ -0,0 +1,288 @@
+package glui
+
+import (
+)
+
+// remembers enough of the last CalcPos call so that an element can be repositioned without recalculating its ancestors
+type layoutState struct {
+  posDirty   bool
+  depthDirty bool
+  calculated bool // CalcPos was called at least once via calcPos()
+
+  // arguments of last CalcPos call
+  maxWidth  int
+  maxHeight int
+
+  // result of last CalcPos call
+  width  int
+  height int
+
+  // translations and crops applied by ancestors since last CalcPos call
+  dx      int
+  dy      int
+  crop    Rect
+  cropped bool
+}
+
+func newLayoutState() layoutState {
+  return layoutState{false, false, false, 0, 0, 0, 0, 0, 0, Rect{0, 0, 0, 0}, false}
+}
+
+// parents must use this instead of calling child.CalcPos() directly
+func calcPos(child Element, maxWidth, maxHeight, maxZIndex int) (int, int) {
+  ls := child.layoutState()
+
+  w, h := child.CalcPos(maxWidth, maxHeight, maxZIndex)
+
+  // translations and crops applied by the child to itself are redone during the next CalcPos
+  ls.maxWidth, ls.maxHeight = maxWidth, maxHeight
+  ls.width, ls.height = w, h
+  ls.dx, ls.dy = 0, 0
+  ls.cropped = false
+  ls.calculated = true
+
+  return w, h
+}
+
+func (ls *layoutState) translate(dx, dy int) {
+  ls.dx += dx
+  ls.dy += dy
+
+  if ls.cropped {
+    ls.crop = ls.crop.Translate(dx, dy)
+  }
+}
+
+func (ls *layoutState) addCrop(r Rect) {
+  if ls.cropped {
+    ls.crop = ls.crop.Common(r)
+  } else {
+    ls.crop = r
+    ls.cropped = true
+  }
+}
+
+// root elements are Body and Menu, returns nil for elements that aren't attached to either
+func rootElement(e Element) Element {
+  for ; elementNotNil(e.Parent()); {
+    e = e.Parent()
+  }
+
+  if _, ok := e.(*Body); ok {
+    return e
+  } else if _, ok := e.(*Menu); ok {
+    return e
+  } else {
+    return nil
+  }
+}
+
+func hasDirtyAncestor(e Element, depth bool) bool {
+  for p := e.Parent(); elementNotNil(p); p = p.Parent() {
+    ls := p.layoutState()
+
+    if (depth && ls.depthDirty) || (!depth && ls.posDirty) {
+      return true
+    }
+  }
+
+  return false
+}
+
+// closerThan dependencies are assumed to point to siblings of an ancestor (or to the ancestor itself)
+// the depth root must be moved upward if such a sibling depends on the subtree, because the subtree is put on top of the stack
+func depthRoot(e Element) Element {
+  root := e
+
+  for p := e; elementNotNil(p.Parent()); p = p.Parent() {
+    for _, sibling := range p.Parent().Children() {
+      if sibling == p {
+        continue
+      }
+
+      for _, dep := range sibling.dependencies() {
+        if hasAncestor(dep, p) {
+          root = p.Parent()
+        }
+      }
+    }
+  }
+
+  return root
+}
+
+// only the subtree of el is recalculated during the next draw, unless its size changes
+func (e *Frame) ForceElementPosDirty(el Element) {
+  if !elementNotNil(el) {
+    return
+  }
+
+  ls := el.layoutState()
+
+  if !ls.posDirty && !ls.depthDirty {
+    e.dirtyElements = append(e.dirtyElements, el)
+  }
+
+  ls.posDirty = true
+}
+
+// children were added or removed
+func (e *Frame) ForceElementDepthDirty(el Element) {
+  e.ForceElementPosDirty(el)
+
+  if elementNotNil(el) {
+    el.layoutState().depthDirty = true
+  }
+}
+
+func (e *Frame) clearDirtyElements() {
+  for _, el := range e.dirtyElements {
+    ls := el.layoutState()
+
+    ls.posDirty = false
+    ls.depthDirty = false
+  }
+
+  e.dirtyElements = make([]Element, 0)
+}
+
+// returns false if a full recalculation is needed
+func (e *Frame) calcDirtyDepth() bool {
+  menuDirty := false
+
+  for _, el := range e.dirtyElements {
+    if el.Deleted() || !el.layoutState().depthDirty || hasDirtyAncestor(el, true) {
+      continue
+    }
+
+    root := rootElement(el)
+    if root == nil {
+      continue
+    } else if e.isMenu(root) {
+      menuDirty = true
+      continue
+    }
+
+    el = depthRoot(el)
+
+    stack := newElementStack()
+    stack.offset = e.bodyTop
+    stack.root = el
+
+    for ; stack.dirty; {
+      stack.dirty = false
+
+      el.CalcDepth(stack)
+    }
+
+    e.bodyTop = stack.maxZIndex()
+    if e.bodyTop >= e.menuOffset - 1 {
+      return false
+    }
+
+    e.ForceElementPosDirty(el)
+  }
+
+  if menuDirty {
+    // menus are small, so always recalculated entirely
+    stack := newElementStack()
+    stack.offset = e.menuOffset
+
+    e.calcMenusDepth(stack)
+
+    if stack.maxZIndex() >= e.maxZIndex {
+      return false
+    }
+  }
+
+  return true
+}
+
+// deeper menu levels are placed on top
+func (e *Frame) calcMenusDepth(stack *ElementStack) {
+  for ; stack.dirty; {
+    stack.dirty = false
+
+    for _, menu := range e.menus() {
+      menu.CalcDepth(stack)
+    }
+  }
+}
+
+// elements that are added during CalcPos (e.g. recycled rows of a ListView) are put on top of the body stack
+func (e *Frame) calcNewDepth(el Element) {
+  stack := newElementStack()
+  stack.offset = e.bodyTop
+  stack.root = el
+
+  for ; stack.dirty; {
+    stack.dirty = false
+
+    el.CalcDepth(stack)
+  }
+
+  e.bodyTop = stack.maxZIndex()
+  if e.bodyTop >= e.menuOffset - 1 {
+    e.depthOverflow = true
+  }
+}
+
+// reposition an element at its previous location, returns false if its size changed
+func (e *Frame) recalcElementPos(el Element) bool {
+  ls := el.layoutState()
+  if !ls.calculated {
+    return false
+  }
+
+  w, h, dx, dy, crop, cropped := ls.width, ls.height, ls.dx, ls.dy, ls.crop, ls.cropped
+
+  calcPos(el, ls.maxWidth, ls.maxHeight, e.maxZIndex)
+
+  el.Translate(dx, dy)
+
+  if cropped {
+    el.Crop(crop)
+  }
+
+  return ls.width == w && ls.height == h
+}
+
+func (e *Frame) calcDirtyPos() {
+  els := e.dirtyElements
+
+  for ; len(els) > 0; {
+    next := make([]Element, 0)
+
+    for _, el := range els {
+      if el.Deleted() || !el.Visible() || !el.layoutState().posDirty || hasDirtyAncestor(el, false) {
+        continue
+      }
+
+      // menus are always recalculated entirely
+      if root := rootElement(el); root == nil || e.isMenu(root) {
+        continue
+      }
+
+      if !e.recalcElementPos(el) {
+        // size change affects siblings, so propagate upward
+        if parent := el.Parent(); elementNotNil(parent) {
+          if !parent.layoutState().posDirty {
+            parent.layoutState().posDirty = true
+            e.dirtyElements = append(e.dirtyElements, parent)
+            next = append(next, parent)
+          }
+        }
+      }
+    }
+
+    for _, el := range els {
+      el.layoutState().posDirty = false
+    }
+
+    els = next
+  }
+
+  e.calcOverlaysPos()
+
+  e.P1.SyncImagesToTexture()
+}
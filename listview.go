@@ -0,0 +1,337 @@
+package glui
+
+import (
+)
+
+//go:generate ./gen_element ListView "CalcDepth appendChild On W"
+
+const (
+  DEFAULT_LIST_LINE_HEIGHT = 25
+  LIST_EXTRA_ROWS = 2 // partially visible rows at the top and bottom
+)
+
+// virtualized list, only the visible rows are materialized
+// must be placed inside an Overflow, which provides the scrolling
+type ListView struct {
+  ElementData
+
+  count      int
+  lineHeight int
+
+  create func() Element // creates a row that can be recycled
+  render func(i int, row Element) // fills a row with item i
+
+  rows  []Element // pool of rows, row j shows item first + j
+  first int
+
+  scrollY  int // offset of the visible part of the list, set during CalcPos
+  selected int // -1 for no selection
+  showSel  bool
+
+  onSelect func(i int)
+}
+
+// rows are Text elements
+func NewListView(count int, text func(i int) string) *ListView {
+  create := func() Element {
+    return NewSans("", 10)
+  }
+
+  render := func(i int, row Element) {
+    txt := row.(*Text)
+
+    if content := text(i); content != txt.Value() {
+      txt.SetContent(content)
+    }
+  }
+
+  return NewElementListView(count, create, render)
+}
+
+func NewElementListView(count int, create func() Element, render func(i int, row Element)) *ListView {
+  e := &ListView{
+    NewElementData(2, 0), // selection quad
+    count,
+    DEFAULT_LIST_LINE_HEIGHT,
+    create, render,
+    make([]Element, 0),
+    0,
+    0,
+    -1,
+    false,
+    nil,
+  }
+
+  e.width = -1
+
+  e.On("click",    e.onMouseClick)
+  e.On("focus",    e.onFocus)
+  e.On("blur",     e.onBlur)
+  e.On("keypress", e.onKeyPress)
+
+  e.syncSelection()
+
+  return e
+}
+
+func (e *ListView) LineHeight(h int) *ListView {
+  e.lineHeight = h
+
+  e.Root.ForceElementPosDirty(e.parent)
+
+  return e
+}
+
+func (e *ListView) OnSelect(fn func(i int)) *ListView {
+  e.onSelect = fn
+
+  return e
+}
+
+func (e *ListView) Len() int {
+  return e.count
+}
+
+// the total height of the list changes, so the Overflow must be recalculated as well
+func (e *ListView) SetCount(count int) {
+  e.count = count
+
+  if e.selected >= count {
+    e.selected = -1
+    e.syncSelection()
+  }
+
+  e.Root.ForceElementPosDirty(e.parent)
+}
+
+// rerenders the visible rows, e.g. when the underlying data changed
+func (e *ListView) Refresh() {
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *ListView) Selected() int {
+  return e.selected
+}
+
+func (e *ListView) Select(i int) {
+  if i < 0 || i >= e.count {
+    return
+  }
+
+  e.selected = i
+
+  e.scrollTo(i)
+
+  e.syncSelection()
+
+  if e.onSelect != nil {
+    e.onSelect(i)
+  }
+}
+
+func (e *ListView) overflow() *Overflow {
+  if oflow, ok := e.parent.(*Overflow); ok {
+    return oflow
+  } else {
+    return nil
+  }
+}
+
+func (e *ListView) totalHeight() int {
+  return e.count*e.lineHeight
+}
+
+// height of the visible part
+func (e *ListView) viewHeight() int {
+  if oflow := e.overflow(); oflow != nil {
+    return oflow.Rect().H - e.Root.P1.Skin.ScrollbarTrackSize()
+  } else {
+    return e.totalHeight()
+  }
+}
+
+// moves the scrollbar of the Overflow so that item i is visible
+func (e *ListView) scrollTo(i int) {
+  oflow := e.overflow()
+  if oflow == nil {
+    return
+  }
+
+  sb := oflow.verScrollbar()
+  if !sb.Visible() || sb.trackLength() <= 0 {
+    return
+  }
+
+  y := i*e.lineHeight
+  if y >= e.scrollY && y + e.lineHeight <= e.scrollY + e.viewHeight() {
+    return
+  } else if y > e.scrollY {
+    // align bottom
+    y = y + e.lineHeight - e.viewHeight()
+  }
+
+  sb.MoveTo(int(float64(y)*float64(sb.trackLength())/float64(e.totalHeight()) + 0.5))
+}
+
+func (e *ListView) syncSelection() {
+  tri0 := e.p1Tris[0]
+  tri1 := e.p1Tris[1]
+
+  if e.selected >= 0 && e.showSel {
+    e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+    e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri0, e.Root.P1.Skin.SelColor())
+    e.Root.P1.SetColorConst(tri1, e.Root.P1.Skin.SelColor())
+  } else {
+    e.Root.P1.SetTriType(tri0, VTYPE_HIDDEN)
+    e.Root.P1.SetTriType(tri1, VTYPE_HIDDEN)
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// the scroll position is taken from the vertical scrollbar of the parent Overflow, which is applied after this CalcPos
+func (e *ListView) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w := e.width
+  if w < 0 || w > maxWidth {
+    w = maxWidth
+  }
+
+  viewH := maxHeight
+  e.scrollY = 0
+
+  if oflow := e.overflow(); oflow != nil {
+    sbTrackSize := e.Root.P1.Skin.ScrollbarTrackSize()
+
+    // avoid the horizontal scrollbar
+    w -= sbTrackSize
+    viewH -= sbTrackSize
+
+    sb := oflow.verScrollbar()
+    if sb.Visible() && sb.trackLength() > 0 {
+      e.scrollY = int(sb.Pos()*float32(e.totalHeight()))
+    }
+  }
+
+  e.first = e.scrollY/e.lineHeight
+
+  n := viewH/e.lineHeight + LIST_EXTRA_ROWS
+  if e.first + n > e.count {
+    n = e.count - e.first
+  }
+
+  if n < 0 {
+    n = 0
+  }
+
+  // rows are only added, never removed, they are hidden instead
+  for ; len(e.rows) < n; {
+    row := e.create()
+
+    e.rows = append(e.rows, row)
+    e.appendChild(row)
+
+    e.Root.calcNewDepth(row)
+  }
+
+  for j, row := range e.rows {
+    if j >= n {
+      if row.Visible() {
+        row.Hide()
+      }
+
+      continue
+    } else if !row.Visible() {
+      row.Show()
+    }
+
+    i := e.first + j
+
+    e.render(i, row)
+
+    if txt, ok := row.(*Text); ok {
+      if i == e.selected && e.showSel {
+        txt.SetColor(WHITE)
+      } else {
+        txt.SetColor(BLACK)
+      }
+    }
+
+    _, rh := calcPos(row, w, e.lineHeight, maxZIndex)
+
+    row.Translate(0, i*e.lineHeight + (e.lineHeight - rh)/2)
+  }
+
+  if e.selected >= 0 {
+    // between the list and the rows
+    z := (normalizeZIndex(e.zIndex, maxZIndex) + normalizeZIndex(e.zIndex + 1, maxZIndex))*0.5
+
+    e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, e.selected*e.lineHeight, w, e.lineHeight}, z)
+  }
+
+  return e.InitRect(w, e.totalHeight())
+}
+
+func (e *ListView) onMouseClick(evt *Event) {
+  _, y := evt.RelPos(e.Rect())
+
+  // the rect is cropped by the Overflow
+  if oflow := e.overflow(); oflow != nil && e.scrollY > 0 {
+    y = evt.Y - oflow.Rect().Y + e.scrollY
+  }
+
+  e.Select(y/e.lineHeight)
+}
+
+func (e *ListView) onFocus(evt *Event) {
+  if evt.IsKeyboardEvent() {
+    e.Root.FocusRect.Show(e)
+  }
+
+  e.showSel = true
+
+  e.syncSelection()
+}
+
+func (e *ListView) onBlur(evt *Event) {
+  e.Root.FocusRect.Hide()
+
+  e.showSel = false
+
+  e.syncSelection()
+}
+
+func (e *ListView) onKeyPress(evt *Event) {
+  pageSize := e.viewHeight()/e.lineHeight
+
+  i := e.selected
+
+  switch evt.Key {
+  case "down":
+    i++
+  case "up":
+    if i < 0 {
+      i = e.count
+    }
+
+    i--
+  case "pagedown":
+    i += pageSize
+  case "pageup":
+    i -= pageSize
+  case "home":
+    i = 0
+  case "end":
+    i = e.count - 1
+  default:
+    return
+  }
+
+  if i < 0 {
+    i = 0
+  } else if i >= e.count {
+    i = e.count - 1
+  }
+
+  e.Select(i)
+}
@@ -0,0 +1,876 @@
+package glui
+
+import (
+  "bytes"
+  "encoding/json"
+  "encoding/xml"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "math"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "time"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+// declarative ui definitions, eg. in xml:
+//  <Ver spacing="10" padding="10">
+//    <Input id="name" width="200"/>
+//    <Button id="submit" caption="Submit"/>
+//  </Ver>
+// or equivalently in json:
+//  {"type": "Ver", "spacing": 10, "padding": 10, "children": [
+//    {"type": "Input", "id": "name", "width": 200},
+//    {"type": "Button", "id": "submit", "caption": "Submit"}
+//  ]}
+// elements are created in the active frame
+
+type Document struct {
+  Root Element
+
+  elements map[string]Element
+}
+
+type LoadError struct {
+  File string // empty if not loaded with LoadFile()
+  Line int
+  Msg  string
+}
+
+func (e *LoadError) Error() string {
+  if e.File != "" {
+    return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+  } else {
+    return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+  }
+}
+
+// returns nil if the id doesn't exist
+func (d *Document) Get(id string) Element {
+  el, ok := d.elements[id]
+  if !ok {
+    return nil
+  }
+
+  return el
+}
+
+// type depends on extension (.xml or .json)
+func LoadFile(fname string) (*Document, error) {
+  data, err := ioutil.ReadFile(fname)
+  if err != nil {
+    return nil, err
+  }
+
+  var doc *Document
+
+  switch strings.ToLower(filepath.Ext(fname)) {
+  case ".xml":
+    doc, err = loadXML(data)
+  case ".json":
+    doc, err = loadJSON(data)
+  default:
+    return nil, fmt.Errorf("%s: unrecognized document extension", fname)
+  }
+
+  if err != nil {
+    if lErr, ok := err.(*LoadError); ok {
+      lErr.File = fname
+    }
+
+    return nil, err
+  }
+
+  return doc, nil
+}
+
+func LoadXML(r io.Reader) (*Document, error) {
+  data, err := ioutil.ReadAll(r)
+  if err != nil {
+    return nil, err
+  }
+
+  return loadXML(data)
+}
+
+func LoadJSON(r io.Reader) (*Document, error) {
+  data, err := ioutil.ReadAll(r)
+  if err != nil {
+    return nil, err
+  }
+
+  return loadJSON(data)
+}
+
+// format independent representation of an element definition
+type docNode struct {
+  typ      string
+  attrs    map[string]string
+  used     map[string]bool
+  content  string
+  children []*docNode
+  line     int
+}
+
+func newDocNode(typ string, line int) *docNode {
+  return &docNode{typ, make(map[string]string), make(map[string]bool), "", make([]*docNode, 0), line}
+}
+
+// lines are counted from 1
+func lineAt(data []byte, offset int64) int {
+  if offset > int64(len(data)) {
+    offset = int64(len(data))
+  }
+
+  return bytes.Count(data[0:offset], []byte("\n")) + 1
+}
+
+func loadXML(data []byte) (*Document, error) {
+  dec := xml.NewDecoder(bytes.NewReader(data))
+
+  var root *docNode = nil
+  stack := make([]*docNode, 0)
+
+  for {
+    offset := dec.InputOffset()
+
+    tok, err := dec.Token()
+    if err == io.EOF {
+      break
+    } else if err != nil {
+      if sErr, ok := err.(*xml.SyntaxError); ok {
+        return nil, &LoadError{"", sErr.Line, sErr.Msg}
+      }
+
+      return nil, &LoadError{"", lineAt(data, offset), err.Error()}
+    }
+
+    switch t := tok.(type) {
+    case xml.StartElement:
+      n := newDocNode(t.Name.Local, lineAt(data, offset))
+
+      for _, attr := range t.Attr {
+        n.attrs[attr.Name.Local] = attr.Value
+      }
+
+      if len(stack) == 0 {
+        if root != nil {
+          return nil, &LoadError{"", n.line, "more than one root element"}
+        }
+
+        root = n
+      } else {
+        parent := stack[len(stack)-1]
+        parent.children = append(parent.children, n)
+      }
+
+      stack = append(stack, n)
+    case xml.EndElement:
+      stack = stack[0:len(stack)-1]
+    case xml.CharData:
+      if len(stack) > 0 {
+        stack[len(stack)-1].content += string(t)
+      }
+    }
+  }
+
+  if root == nil {
+    return nil, &LoadError{"", lineAt(data, int64(len(data))), "no root element"}
+  }
+
+  return buildDocument(root)
+}
+
+type jsonParser struct {
+  data []byte
+  dec  *json.Decoder
+}
+
+func loadJSON(data []byte) (*Document, error) {
+  p := &jsonParser{data, json.NewDecoder(bytes.NewReader(data))}
+
+  root, err := p.parseRoot()
+  if err != nil {
+    return nil, err
+  }
+
+  return buildDocument(root)
+}
+
+func (p *jsonParser) line() int {
+  return lineAt(p.data, p.dec.InputOffset())
+}
+
+func (p *jsonParser) errorf(format string, args ...interface{}) error {
+  return &LoadError{"", p.line(), fmt.Sprintf(format, args...)}
+}
+
+func (p *jsonParser) token() (json.Token, error) {
+  tok, err := p.dec.Token()
+  if err != nil {
+    if sErr, ok := err.(*json.SyntaxError); ok {
+      return nil, &LoadError{"", lineAt(p.data, sErr.Offset), sErr.Error()}
+    } else if err == io.EOF {
+      return nil, p.errorf("unexpected end of document")
+    }
+
+    return nil, p.errorf("%s", err.Error())
+  }
+
+  return tok, nil
+}
+
+func (p *jsonParser) expectDelim(d json.Delim) error {
+  tok, err := p.token()
+  if err != nil {
+    return err
+  }
+
+  if tok != d {
+    return p.errorf("expected '%s'", d.String())
+  }
+
+  return nil
+}
+
+func (p *jsonParser) parseRoot() (*docNode, error) {
+  if err := p.expectDelim('{'); err != nil {
+    return nil, err
+  }
+
+  root, err := p.parseObject()
+  if err != nil {
+    return nil, err
+  }
+
+  if _, err := p.dec.Token(); err != io.EOF {
+    return nil, p.errorf("more than one root element")
+  }
+
+  return root, nil
+}
+
+func (p *jsonParser) parseScalar() (string, error) {
+  tok, err := p.token()
+  if err != nil {
+    return "", err
+  }
+
+  switch v := tok.(type) {
+  case string:
+    return v, nil
+  case float64:
+    return strconv.FormatFloat(v, 'f', -1, 64), nil
+  case bool:
+    return strconv.FormatBool(v), nil
+  default:
+    return "", p.errorf("expected string, number or bool")
+  }
+}
+
+// the opening brace has already been consumed
+func (p *jsonParser) parseObject() (*docNode, error) {
+  n := newDocNode("", p.line())
+
+  for ; p.dec.More(); {
+    tok, err := p.token()
+    if err != nil {
+      return nil, err
+    }
+
+    key := tok.(string)
+
+    switch key {
+    case "type":
+      n.typ, err = p.parseScalar()
+    case "content":
+      n.content, err = p.parseScalar()
+    case "children":
+      err = p.parseChildren(n)
+    default:
+      n.attrs[key], err = p.parseScalar()
+    }
+
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  if err := p.expectDelim('}'); err != nil {
+    return nil, err
+  }
+
+  if n.typ == "" {
+    return nil, &LoadError{"", n.line, "missing type"}
+  }
+
+  return n, nil
+}
+
+func (p *jsonParser) parseChildren(n *docNode) error {
+  if err := p.expectDelim('['); err != nil {
+    return err
+  }
+
+  for ; p.dec.More(); {
+    if err := p.expectDelim('{'); err != nil {
+      return err
+    }
+
+    child, err := p.parseObject()
+    if err != nil {
+      return err
+    }
+
+    n.children = append(n.children, child)
+  }
+
+  return p.expectDelim(']')
+}
+
+// node methods panic with a *LoadError, which is recovered in buildDocument()
+func (n *docNode) errorf(format string, args ...interface{}) {
+  panic(&LoadError{"", n.line, fmt.Sprintf(format, args...)})
+}
+
+func (n *docNode) attr(name string) (string, bool) {
+  v, ok := n.attrs[name]
+  if ok {
+    n.used[name] = true
+  }
+
+  return v, ok
+}
+
+func (n *docNode) str(name string, def string) string {
+  if v, ok := n.attr(name); ok {
+    return v
+  } else {
+    return def
+  }
+}
+
+func (n *docNode) int(name string, def int) int {
+  v, ok := n.attr(name)
+  if !ok {
+    return def
+  }
+
+  i, err := strconv.Atoi(strings.TrimSpace(v))
+  if err != nil {
+    n.errorf("%s: expected integer, got \"%s\"", name, v)
+  }
+
+  return i
+}
+
+// space separated
+func (n *docNode) ints(name string) ([]int, bool) {
+  v, ok := n.attr(name)
+  if !ok {
+    return nil, false
+  }
+
+  res := make([]int, 0)
+  for _, field := range strings.Fields(v) {
+    i, err := strconv.Atoi(field)
+    if err != nil {
+      n.errorf("%s: expected integers, got \"%s\"", name, v)
+    }
+
+    res = append(res, i)
+  }
+
+  return res, true
+}
+
+func (n *docNode) float(name string, def float64) float64 {
+  v, ok := n.attr(name)
+  if !ok {
+    return def
+  }
+
+  f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+  if err != nil {
+    n.errorf("%s: expected number, got \"%s\"", name, v)
+  }
+
+  return f
+}
+
+// formatted like DATE_FMT
+func (n *docNode) date(name string) time.Time {
+  v, ok := n.attr(name)
+  if !ok {
+    return time.Time{}
+  }
+
+  t, err := time.Parse(DATE_FMT, strings.TrimSpace(v))
+  if err != nil {
+    n.errorf("%s: expected date like %s, got \"%s\"", name, DATE_FMT, v)
+  }
+
+  return t
+}
+
+// #rrggbb or #rrggbbaa
+func (n *docNode) color(name string, def sdl.Color) sdl.Color {
+  v, ok := n.attr(name)
+  if !ok {
+    return def
+  }
+
+  c, err := parseHexColor(v)
+  if err != nil {
+    n.errorf("%s: expected color like #rrggbb, got \"%s\"", name, v)
+  }
+
+  return c
+}
+
+func (n *docNode) bool(name string, def bool) bool {
+  v, ok := n.attr(name)
+  if !ok {
+    return def
+  }
+
+  b, err := strconv.ParseBool(strings.TrimSpace(v))
+  if err != nil {
+    n.errorf("%s: expected true or false, got \"%s\"", name, v)
+  }
+
+  return b
+}
+
+func (n *docNode) align(name string, def Align) Align {
+  v, ok := n.attr(name)
+  if !ok {
+    return def
+  }
+
+  switch strings.ToLower(strings.TrimSpace(v)) {
+  case "start":
+    return START
+  case "center":
+    return CENTER
+  case "end":
+    return END
+  case "stretch":
+    return STRETCH
+  default:
+    n.errorf("%s: expected start, center, end or stretch, got \"%s\"", name, v)
+    return def
+  }
+}
+
+func (n *docNode) orientation(name string, def Orientation) Orientation {
+  v, ok := n.attr(name)
+  if !ok {
+    return def
+  }
+
+  switch strings.ToLower(strings.TrimSpace(v)) {
+  case "hor":
+    return HOR
+  case "ver":
+    return VER
+  default:
+    n.errorf("%s: expected hor or ver, got \"%s\"", name, v)
+    return def
+  }
+}
+
+func (n *docNode) text() string {
+  return strings.TrimSpace(n.content)
+}
+
+func (n *docNode) checkAttrs() {
+  for name := range n.attrs {
+    if !n.used[name] {
+      n.errorf("unknown attribute \"%s\" for %s", name, n.typ)
+    }
+  }
+}
+
+func (n *docNode) checkNoContent() {
+  if n.text() != "" {
+    n.errorf("%s can't have text content", n.typ)
+  }
+}
+
+func (n *docNode) checkNoChildren() {
+  if len(n.children) > 0 {
+    n.errorf("%s can't have children", n.typ)
+  }
+}
+
+// returns the text content of child nodes of the given type
+func (n *docNode) childTexts(typ string) []string {
+  res := make([]string, 0)
+
+  for _, child := range n.children {
+    if child.typ != typ {
+      child.errorf("expected %s in %s, got %s", typ, n.typ, child.typ)
+    }
+
+    child.checkAttrs()
+    child.checkNoChildren()
+
+    res = append(res, child.text())
+  }
+
+  return res
+}
+
+type docBuilder struct {
+  elements map[string]Element
+  built    []Element // deleted again if the document turns out to be invalid
+}
+
+func buildDocument(root *docNode) (doc *Document, err error) {
+  var b *docBuilder
+
+  defer func() {
+    if r := recover(); r != nil {
+      lErr, ok := r.(*LoadError)
+      if !ok {
+        panic(r)
+      }
+
+      b.deleteBuilt()
+
+      doc, err = nil, lErr
+    }
+  }()
+
+  b = &docBuilder{make(map[string]Element), make([]Element, 0)}
+
+  el := b.build(root)
+
+  return &Document{el, b.elements}, nil
+}
+
+// called as soon as an element is created, before its attributes can cause an error
+func (b *docBuilder) track(el Element) {
+  b.built = append(b.built, el)
+}
+
+// elements that were already appended to a parent are deleted along with it
+func (b *docBuilder) deleteBuilt() {
+  for _, el := range b.built {
+    if !el.Deleted() && !elementNotNil(el.Parent()) {
+      el.Delete()
+    }
+  }
+
+  b.built = nil
+}
+
+func (b *docBuilder) register(n *docNode, el Element) {
+  id, ok := n.attr("id")
+  if !ok {
+    return
+  }
+
+  if _, exists := b.elements[id]; exists {
+    n.errorf("duplicate id \"%s\"", id)
+  }
+
+  b.elements[id] = el
+}
+
+// width, height, padding and spacing
+func (b *docBuilder) applyPositioning(n *docNode, el Element) {
+  d := el.elementData()
+
+  d.width = n.int("width", d.width)
+  d.height = n.int("height", d.height)
+  d.spacing = n.int("spacing", d.spacing)
+
+  if p, ok := n.ints("padding"); ok {
+    switch len(p) {
+    case 1:
+      d.padding = [4]int{p[0], p[0], p[0], p[0]}
+    case 2:
+      d.padding = [4]int{p[0], p[1], p[0], p[1]}
+    case 3:
+      d.padding = [4]int{p[0], p[1], p[0], p[2]}
+    case 4:
+      d.padding = [4]int{p[0], p[1], p[2], p[3]}
+    default:
+      n.errorf("padding: expected 1 to 4 integers")
+    }
+  }
+
+  d.Root.ForceElementPosDirty(el)
+}
+
+func (b *docBuilder) build(n *docNode) Element {
+  var el Element
+
+  childrenDone := false // some elements take care of their own children
+
+  switch n.typ {
+  case "Hor":
+    el = NewHor(n.align("halign", START), n.align("valign", START), 0)
+  case "Ver":
+    el = NewVer(n.align("valign", START), n.align("halign", START), 0)
+  case "Button":
+    el = b.buildButton(n)
+  case "Text":
+    font := n.str("font", "sans")
+    switch font {
+    case "sans":
+      font = DEFAULT_SANS
+    case "mono":
+      font = DEFAULT_MONO
+    }
+
+    el = NewText(n.text(), font, n.float("size", 10))
+  case "Input":
+    el = NewInput()
+  case "Checkbox":
+    el = NewCheckbox()
+  case "Icon":
+    el = NewIcon(n.str("name", ""), n.int("size", 20))
+  case "Select":
+    el = NewSelect(n.childTexts("Option"))
+    childrenDone = true
+  case "ComboBox":
+    combo := NewComboBox(n.childTexts("Option"))
+    b.track(combo)
+    if n.str("mode", "prefix") == "fuzzy" {
+      combo.Mode(COMBOBOX_FUZZY)
+    }
+    combo.SetValue(n.str("value", ""))
+    el = combo
+    childrenDone = true
+  case "RadioGroup":
+    el = NewRadioGroup(n.childTexts("Option"), n.orientation("orientation", VER))
+    childrenDone = true
+  case "Table":
+    el = b.buildTable(n)
+    childrenDone = true
+  case "Tabbed":
+    el = b.buildTabbed(n)
+    childrenDone = true
+  case "VSplit":
+    vsplit := NewVSplit()
+    b.track(vsplit)
+    if minIntervals, ok := n.ints("minintervals"); ok {
+      vsplit.MinIntervals(minIntervals)
+    }
+
+    el = vsplit
+  case "Overflow":
+    el = NewOverflow()
+  case "Slider":
+    slider := NewSlider(n.orientation("orientation", HOR))
+    b.track(slider)
+
+    min, max := n.float("min", 0.0), n.float("max", 1.0)
+    if max < min {
+      n.errorf("max smaller than min")
+    }
+
+    slider.Range(min, max)
+    slider.Step(n.float("step", 0.0))
+    slider.Ticks(n.float("ticks", 0.0))
+    slider.SetValue(n.float("value", 0.0))
+
+    el = slider
+  case "SpinBox":
+    spin := NewSpinBox(n.int("decimals", 0))
+    b.track(spin)
+
+    min, max := n.float("min", math.Inf(-1)), n.float("max", math.Inf(1))
+    if max < min {
+      n.errorf("max smaller than min")
+    }
+
+    spin.Range(min, max)
+    spin.Step(n.float("step", 1.0))
+    spin.SetValue(n.float("value", 0.0))
+
+    el = spin
+  case "ProgressBar":
+    bar := NewProgressBar()
+    b.track(bar)
+    bar.ShowCaption(n.bool("caption", false))
+    bar.SetIndeterminate(n.bool("indeterminate", false))
+    bar.SetValue(n.float("value", 0.0))
+
+    el = bar
+  case "ColorPicker":
+    el = NewColorPicker(n.color("value", WHITE))
+  case "DatePicker":
+    picker := NewDatePicker()
+    b.track(picker)
+    picker.Range(n.date("min"), n.date("max"))
+    picker.SetValue(n.date("value"))
+
+    el = picker
+  case "Spinner":
+    el = NewSpinner(n.int("size", 20))
+  default:
+    n.errorf("unknown element type %s", n.typ)
+  }
+
+  b.track(el)
+
+  if n.typ != "Text" {
+    n.checkNoContent()
+  }
+
+  b.register(n, el)
+  b.applyPositioning(n, el)
+
+  if !childrenDone {
+    b.appendChildren(n, el)
+  }
+
+  if !n.bool("enabled", true) {
+    el.Disable()
+  }
+
+  n.checkAttrs()
+
+  return el
+}
+
+func (b *docBuilder) appendChildren(n *docNode, el Element) {
+  if len(n.children) == 0 {
+    return
+  }
+
+  for _, child := range n.children {
+    switch parent := el.(type) {
+    case Container:
+      parent.A(b.build(child))
+    case interface{A(children ...Element) Element}:
+      parent.A(b.build(child))
+    default:
+      n.checkNoChildren()
+    }
+  }
+}
+
+func (b *docBuilder) buildButton(n *docNode) *Button {
+  flat := n.bool("flat", false)
+
+  if caption, ok := n.attr("caption"); ok {
+    button := NewCaptionButton(caption)
+    if flat {
+      button.flat = true
+      button.setTypesAndTCoords(false)
+    }
+
+    return button
+  } else if icon, ok := n.attr("icon"); ok {
+    iconSize := n.int("iconsize", 20)
+
+    if flat {
+      return NewFlatIconButton(icon, iconSize)
+    } else {
+      return NewIconButton(icon, iconSize, n.orientation("orientation", HOR))
+    }
+  } else if flat {
+    return NewFlatButton()
+  } else {
+    return NewButton()
+  }
+}
+
+func (b *docBuilder) buildTable(n *docNode) *Table {
+  table := NewTable()
+  b.track(table)
+
+  table.MasterColumn(n.int("mastercolumn", table.masterCol))
+
+  rows := make([][]interface{}, 0)
+  rowNodes := make([]*docNode, 0) // for the line numbers of the errors
+
+  for _, child := range n.children {
+    switch child.typ {
+    case "TextColumn":
+      table.A(NewTextColumn(child.str("caption", "")))
+    case "DateColumn":
+      table.A(NewDateColumn(child.str("caption", "")))
+    case "NumberColumn":
+      format := child.str("format", DEFAULT_NUMBER_FMT)
+      if !validNumberFormat(format) {
+        child.errorf("invalid number format %q", format)
+      }
+
+      table.A(NewNumberColumn(child.str("caption", ""), format))
+    case "BoolColumn":
+      table.A(NewBoolColumn(child.str("caption", "")))
+    case "IconColumn":
+      table.A(NewIconColumn(child.str("caption", ""), child.int("size", 10)))
+    case "ProgressColumn":
+      table.A(NewProgressColumn(child.str("caption", "")))
+    case "Row":
+      row := make([]interface{}, 0)
+      for _, cell := range child.childTexts("Cell") {
+        row = append(row, cell)
+      }
+
+      rows = append(rows, row)
+      rowNodes = append(rowNodes, child)
+    default:
+      child.errorf("expected a column or Row in Table, got %s", child.typ)
+    }
+
+    child.checkNoContent()
+    child.checkAttrs()
+  }
+
+  // rows can only be added once all columns are known
+  for i, row := range rows {
+    rowNode := rowNodes[i]
+
+    if len(row) != len(table.body.children) {
+      rowNode.errorf("row %d: expected %d cells, got %d", i, len(table.body.children), len(row))
+    }
+
+    // cells are parsed by their column, so the model contains proper values
+    for j, cell := range row {
+      x, err := table.body.getColumn(j).Parse(cell.(string))
+      if err != nil {
+        rowNode.children[j].errorf("row %d, cell %d: %s", i, j, err.Error())
+      }
+
+      row[j] = x
+    }
+
+    table.AddRow(row...)
+  }
+
+  return table
+}
+
+func (b *docBuilder) buildTabbed(n *docNode) *Tabbed {
+  tabbed := NewTabbed()
+  b.track(tabbed)
+
+  for _, child := range n.children {
+    if child.typ != "Tab" {
+      child.errorf("expected Tab in Tabbed, got %s", child.typ)
+    }
+
+    tab := tabbed.NewTab(child.str("caption", ""), child.bool("closeable", false))
+
+    child.checkNoContent()
+
+    b.register(child, tab)
+    b.applyPositioning(child, tab)
+    b.appendChildren(child, tab)
+
+    child.checkAttrs()
+  }
+
+  return tabbed
+}
@@ -1,32 +1,55 @@
 package glui
 
 import (
+  "github.com/veandco/go-sdl2/sdl"
 )
 
-//go:generate ./gen_element Menu "A CalcDepth Padding Spacing"
+//go:generate ./gen_element Menu "A CalcDepth On Padding Spacing"
+
+//go:generate ./gen_element menuSeparator "CalcDepth"
+
+const (
+  MENU_SCROLL_SPEED     = 6
+  MENU_SEPARATOR_HEIGHT = 9
+)
+
+var MENU_SEPARATOR_COLOR = sdl.Color{0xc0, 0xc0, 0xc0, 0xff}
 
 // styled the same as a button, and can be filled with arbitrary children
+// the frame menu is level 0, submenus are opened beside an item of the previous level
 type Menu struct {
   ElementData
 
+  level int
+
   // state 
   anchor  Element // XXX: what if anchor is deleted?
   anchorX float64
   anchorY float64
+  beside  bool // beside the anchor instead of at an anchor point
+  scroll  int  // only used if the menu is taller than the window or the max height
+
+  maxHeight int // 0 for the window height, reset by ClearChildren()
 }
 
-func newMenu(frame *Frame) *Menu {
+func newMenu(frame *Frame, level int) *Menu {
   e := &Menu{
     newElementData(frame, 9*2, 0),
+    level,
     nil,
     0.0,
     0.0,
+    false,
+    0,
+    0,
   }
 
   e.setTypesAndTCoords()
 
   e.Padding(e.Root.P1.Skin.ButtonBorderThickness())
 
+  e.On("wheel", e.onWheel)
+
   e.Hide()
 
   return e
@@ -46,8 +69,26 @@ func (e *Menu) ShowAt(anchor Element, anchorX, anchorY float64, width int) {
   e.anchor  = anchor
   e.anchorX = anchorX
   e.anchorY = anchorY
+  e.beside  = false
   e.width   = width
 
+  e.show()
+}
+
+// the first child is aligned with the anchor, the menu is placed on the other side if there isn't enough room
+func (e *Menu) ShowBeside(anchor Element, width int) {
+  e.anchor = anchor
+  e.beside = true
+  e.width  = width
+
+  e.show()
+}
+
+func (e *Menu) show() {
+  e.hideSubMenu()
+
+  e.scroll = 0
+
   e.ElementData.Show()
 
   for i, tri := range e.p1Tris {
@@ -58,7 +99,7 @@ func (e *Menu) ShowAt(anchor Element, anchorX, anchorY float64, width int) {
     }
   }
 
-  e.Root.ForcePosDirty()
+  e.Root.forceOverlaysPosDirty()
 }
 
 func (e *Menu) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
@@ -70,20 +111,54 @@ func (e *Menu) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
 
   w, h := e.GetSize()
 
+  // bound by window
+  W, H := e.Root.GetSize()
+
+  // the children are laid out at full height, and then scrolled and cropped
+  if vh := e.visibleHeight(); h > vh {
+    h = vh
+  }
+
+  e.scroll = e.clampScroll(e.scroll)
+
   e.SetBorderedElementPos(w, h, t, maxZIndex)
 
-  e.ElementData.CalcPosChildren(w, h, maxZIndex)
+  e.ElementData.CalcPosChildren(w, e.height, maxZIndex)
+
+  if h < e.height {
+    inner := Rect{t, t, w - 2*t, h - 2*t}
+
+    for _, child := range e.children {
+      child.Translate(0, -e.scroll)
+      child.Crop(inner)
+    }
+  }
 
   e.InitRect(w, h)
 
-  r := e.anchor.Rect()
+  // anchor is already positioned inside the window, but the menu is translated to the frame position afterwards
+  fx, fy := e.Root.GetPos()
+  r := e.anchor.Rect().Translate(-fx, -fy)
   x_, y_ := r.Pos(e.anchorX, e.anchorY)
 
   x := x_ - int(e.anchorX*float64(w))
   y := y_ - int((1.0 - e.anchorY)*float64(h))
 
-  // bound by window
-  W, H := e.Root.GetSize()
+  if e.beside {
+    y = r.Y - t
+
+    if isRTL() {
+      x = r.X - w
+      if x < 0 {
+        x = r.Right()
+      }
+    } else {
+      x = r.Right()
+      if x + w > W {
+        x = r.X - w
+      }
+    }
+  }
 
   if x < 0 {
     x = 0
@@ -104,17 +179,20 @@ func (e *Menu) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
 
 func (e *Menu) ClearChildren() {
   e.height = 2*e.Root.P1.Skin.ButtonBorderThickness()
+  e.maxHeight = 0
 
   e.ElementData.ClearChildren()
 }
 
 func (e *Menu) AddItem(item *MenuItem, enabled bool, selected bool) {
-  // add mouseupeventlistener to close the menu
+  item.menu = e
+
+  // add mouseupeventlistener to close the menu, and all the levels before it
   item.On("mouseup", func(evt *Event) {
-    if e.Visible() {
-      e.Hide()
-    } else {
+    if !e.Visible() {
       evt.stopPropagation = true
+    } else if item.subMenu == nil && item.enabled {
+      e.Root.Menu.Hide()
     }
   })
 
@@ -131,12 +209,202 @@ func (e *Menu) AddItem(item *MenuItem, enabled bool, selected bool) {
   e.A(item)
 
   e.height += item.height
+
+  e.alignMenuItemLeads()
+}
+
+// items created from configs share the height of the first item, separators are inserted as is
+func (e *Menu) AddConfigItems(configs []MenuItemConfig, itemHeight int) []*MenuItem {
+  items := make([]*MenuItem, 0)
+
+  for _, cfg := range configs {
+    if cfg.Kind == MENU_ITEM_SEPARATOR {
+      e.AddSeparator()
+    } else {
+      item := newMenuItemFromConfig(cfg).H(itemHeight)
+
+      e.AddItem(item, !cfg.Disabled, false)
+
+      items = append(items, item)
+    }
+  }
+
+  return items
+}
+
+func (e *Menu) AddSeparator() {
+  sep := newMenuSeparator()
+
+  e.A(sep)
+
+  e.height += sep.height
+}
+
+// the captions are aligned if any of the items has a check mark or an icon
+func (e *Menu) alignMenuItemLeads() {
+  leadW := 0
+
+  for _, child_ := range e.children {
+    if child, ok := child_.(*MenuItem); ok && child.hasLead() {
+      leadW = MENUITEM_LEAD_WIDTH
+    }
+  }
+
+  for _, child_ := range e.children {
+    if child, ok := child_.(*MenuItem); ok && child.leadW != leadW {
+      child.leadW = leadW
+      e.Root.ForceElementPosDirty(child)
+    }
+  }
+}
+
+// unchecks the other radio items of the group, which is delimited by any other kind of child
+func (e *Menu) checkRadioItem(item *MenuItem) {
+  isRadio := func(el Element) bool {
+    child, ok := el.(*MenuItem)
+    return ok && child.kind == MENU_ITEM_RADIO
+  }
+
+  start := 0
+  for i, child := range e.children {
+    if child == Element(item) {
+      break
+    } else if !isRadio(child) {
+      start = i + 1
+    }
+  }
+
+  for _, child := range e.children[start:] {
+    if !isRadio(child) {
+      break
+    }
+
+    radio := child.(*MenuItem)
+
+    *radio.checked = radio == item
+
+    radio.lead.setTypesAndTCoords()
+  }
+}
+
+// longer menus scroll, must be set after ClearChildren()
+func (e *Menu) MaxHeight(h int) *Menu {
+  e.maxHeight = h
+
+  return e
+}
+
+func (e *Menu) visibleHeight() int {
+  _, H := e.Root.GetSize()
+
+  if e.maxHeight > 0 && e.maxHeight < H {
+    return e.maxHeight
+  } else {
+    return H
+  }
+}
+
+// 0 if the menu fits
+func (e *Menu) clampScroll(scroll int) int {
+  maxScroll := e.height - e.visibleHeight()
+  if maxScroll < 0 {
+    maxScroll = 0
+  }
+
+  if scroll > maxScroll {
+    return maxScroll
+  } else if scroll < 0 {
+    return 0
+  } else {
+    return scroll
+  }
+}
+
+func (e *Menu) scrollTo(scroll int) {
+  scroll = e.clampScroll(scroll)
+
+  if scroll != e.scroll {
+    e.scroll = scroll
+
+    e.hideSubMenu()
+
+    e.Root.forceOverlaysPosDirty()
+  }
+}
+
+func (e *Menu) onWheel(evt *Event) {
+  if e.Visible() {
+    e.scrollTo(e.scroll + evt.YRel*MENU_SCROLL_SPEED)
+  }
+}
+
+// scroll so the selected item is completely visible
+func (e *Menu) scrollToSelected() {
+  H := e.visibleHeight()
+
+  t := e.Root.P1.Skin.ButtonBorderThickness()
+
+  y := t
+  for _, child_ := range e.children {
+    if !child_.Visible() {
+      continue
+    }
+
+    _, ch := child_.elementData().GetSize()
+
+    if child, ok := child_.(*MenuItem); ok && child.Selected() {
+      if y - e.scroll < t {
+        e.scrollTo(y - t)
+      } else if y + ch - e.scroll > H - t {
+        e.scrollTo(y + ch - H + t)
+      }
+
+      return
+    }
+
+    y += ch
+  }
 }
 
 func (e *Menu) IsOwnedBy(el Element) bool {
   return e.anchor == el && e.Visible()
 }
 
+// the menu of the next level, created if needed
+func (e *Menu) SubMenu() *Menu {
+  return e.Root.menuLevel(e.level + 1)
+}
+
+func (e *Menu) subMenuVisible() bool {
+  return e.level < len(e.Root.subMenus) && e.Root.subMenus[e.level].Visible()
+}
+
+func (e *Menu) hideSubMenu() {
+  if e.subMenuVisible() {
+    e.Root.subMenus[e.level].Hide()
+  }
+}
+
+// also hides the deeper levels
+func (e *Menu) Hide() {
+  e.hideSubMenu()
+
+  e.ElementData.Hide()
+}
+
+// widest child, for the width argument of ShowAt or ShowBeside
+func (e *Menu) ChildrenWidth() int {
+  w := 0
+
+  for _, child := range e.children {
+    if cw, _ := child.elementData().GetSize(); cw > w {
+      w = cw
+    }
+  }
+
+  return w
+}
+
 func (e *Menu) loopMenuItems(fn func(i int, item *MenuItem)) int {
   c := 0
 
@@ -166,6 +434,19 @@ func (e *Menu) SelectedIndex() int {
   return found
 }
 
+// nil if no item is selected
+func (e *Menu) selectedItem() *MenuItem {
+  var res *MenuItem = nil
+
+  e.loopMenuItems(func(i int, item *MenuItem) {
+    if res == nil && item.Selected() {
+      res = item
+    }
+  })
+
+  return res
+}
+
 func (e *Menu) unselectOtherMenuItems(item *MenuItem) {
   e.loopMenuItems(func(_ int, otherItem *MenuItem) {
     if otherItem != item {
@@ -187,6 +468,8 @@ func (e *Menu) SelectNext() {
       item.Unselect()
     }
   })
+
+  e.scrollToSelected()
 }
 
 func (e *Menu) SelectPrev() {
@@ -213,6 +496,31 @@ func (e *Menu) SelectPrev() {
       }
     })
   }
+
+  e.scrollToSelected()
+}
+
+// i is clamped to the enabled items, without wrapping
+func (e *Menu) SelectIndex(i int) {
+  c := e.countMenuItems()
+
+  if i > c - 1 {
+    i = c - 1
+  }
+
+  if i < 0 {
+    i = 0
+  }
+
+  e.loopMenuItems(func(j int, item *MenuItem) {
+    if j == i {
+      item.Select()
+    } else {
+      item.Unselect()
+    }
+  })
+
+  e.scrollToSelected()
 }
 
 func (e *Menu) ClickSelected() {
@@ -220,16 +528,62 @@ func (e *Menu) ClickSelected() {
     // onClick must be called after menu is hidden (to avoid double click issues), but we must find the selected item before the menu is hidden (because the selected state is reset upon hiding the menu)
     var fn func() = nil
 
-    e.loopMenuItems(func(i int, item *MenuItem) {
-      if item.Selected() {
-        fn = item.onClick
-      }
-    })
+    sel := e.selectedItem()
+    if sel != nil {
+      fn = sel.click
+    }
+
+    // items with a submenu aren't clicked, the submenu is opened instead
+    if sel != nil && sel.subMenu != nil {
+      sel.openSubMenu()
+
+      e.SubMenu().SelectNext()
+
+      return
+    }
 
-    e.Hide()
+    e.Root.Menu.Hide()
 
     if fn != nil {
       fn()
     }
   }
 }
+
+// horizontal line between groups of items
+type menuSeparator struct {
+  ElementData
+}
+
+func newMenuSeparator() *menuSeparator {
+  e := &menuSeparator{
+    NewElementData(2, 0),
+  }
+
+  e.height = MENU_SEPARATOR_HEIGHT
+
+  e.setTypesAndColor()
+
+  return e
+}
+
+func (e *menuSeparator) setTypesAndColor() {
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri, MENU_SEPARATOR_COLOR)
+  }
+}
+
+func (e *menuSeparator) Show() {
+  e.ElementData.Show()
+
+  e.setTypesAndColor()
+}
+
+func (e *menuSeparator) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := maxWidth, e.height
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{4, h/2, w - 8, 1}, e.Z(maxZIndex))
+
+  return e.InitRect(w, h)
+}
@@ -5,6 +5,7 @@ func (e *Menu) A(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -15,6 +16,16 @@ func (e *Menu) CalcDepth(stack *ElementStack) {
   }
 }
 
+func (e *Menu) On(name string, fn EventListener) *Menu {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
 func (e *Menu) Padding(p ...int) *Menu {
   switch len(p) {
   case 1:
@@ -32,13 +43,13 @@ func (e *Menu) Padding(p ...int) *Menu {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Menu) Spacing(s int) *Menu {
   e.spacing = s
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -0,0 +1,376 @@
+package glui
+
+import (
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element MenuBar "CalcDepth appendChild On H"
+//go:generate ./gen_element menuBarItem "CalcDepth appendChild On"
+
+const (
+  MENUBAR_HEIGHT       = 26
+  MENUBAR_ITEM_PADDING = 10
+  MENUBAR_MENU_ITEM_HEIGHT = 30
+)
+
+// row of top-level menus, meant to be the first child of the body
+// pressing and releasing alt activates the bar, after which the menus can be navigated with the arrow keys
+// the menus are shown in the frame menu, submenus in the next menu levels
+type MenuBar struct {
+  ElementData
+
+  items []*menuBarItem
+
+  current   int     // highlighted item, -1 if none
+  active    bool    // the bar has the focus
+  prevFocus Element // focused before the bar was activated
+}
+
+func NewMenuBar() *MenuBar {
+  e := &MenuBar{
+    NewElementData(2, 0),
+    make([]*menuBarItem, 0),
+    -1,
+    false,
+    nil,
+  }
+
+  e.height = MENUBAR_HEIGHT
+
+  e.setTypesAndColor()
+
+  // only one menu bar per frame
+  e.Root.menuBar = e
+
+  e.On("focus", e.onFocus)
+  e.On("blur", e.onBlur)
+  e.On("keypress", e.onKeyPress)
+
+  return e
+}
+
+// the callbacks of the items are called after the bar is deactivated
+func (e *MenuBar) AddMenu(caption string, items []MenuItemConfig) *MenuBar {
+  item := newMenuBarItem(e, len(e.items), caption, e.wrapConfigs(items))
+
+  e.items = append(e.items, item)
+
+  e.appendChild(item)
+
+  return e
+}
+
+func (e *MenuBar) wrapConfigs(items []MenuItemConfig) []MenuItemConfig {
+  res := make([]MenuItemConfig, len(items))
+
+  for i, cfg := range items {
+    callback := cfg.Callback
+
+    cfg.Callback = func() {
+      e.deactivate(true)
+
+      if callback != nil {
+        callback()
+      }
+    }
+
+    cfg.SubMenu = e.wrapConfigs(cfg.SubMenu)
+
+    res[i] = cfg
+  }
+
+  return res
+}
+
+func (e *MenuBar) setTypesAndColor() {
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri, e.Root.P1.Skin.BGColor())
+  }
+}
+
+// only focusable while active, so tab skips the bar
+func (e *MenuBar) IsFocusable() bool {
+  return e.ElementData.IsFocusable() && e.active
+}
+
+func (e *MenuBar) menuVisible() bool {
+  return e.current >= 0 && e.Root.Menu.IsOwnedBy(e.items[e.current])
+}
+
+func (e *MenuBar) setCurrent(i int) {
+  e.current = i
+
+  for _, item := range e.items {
+    item.setTypesAndColor()
+  }
+}
+
+func (e *MenuBar) activate() {
+  if e.active {
+    return
+  }
+
+  e.active = true
+
+  if focus := e.Root.state.focusElement; focus != Element(e) {
+    e.prevFocus = focus
+  }
+
+  Focus(e)
+}
+
+// restoreFocus is false if the focus is already moving elsewhere
+func (e *MenuBar) deactivate(restoreFocus bool) {
+  if e.menuVisible() {
+    e.Root.Menu.Hide()
+  }
+
+  if !e.active {
+    return
+  }
+
+  e.active = false
+
+  e.setCurrent(-1)
+
+  prev := e.prevFocus
+  e.prevFocus = nil
+
+  if restoreFocus {
+    if elementNotNil(prev) && !prev.Deleted() && prev.Visible() {
+      Focus(prev)
+    } else {
+      Focus(nil)
+    }
+  }
+}
+
+// called by the app when alt is pressed and released by itself
+func (e *MenuBar) toggle() {
+  if e.active {
+    e.deactivate(true)
+  } else if len(e.items) > 0 {
+    e.activate()
+
+    e.setCurrent(0)
+  }
+}
+
+func (e *MenuBar) openMenu(i int) {
+  e.activate()
+
+  e.setCurrent(i)
+
+  item := e.items[i]
+
+  menu := e.Root.Menu
+
+  menu.ClearChildren()
+
+  menu.AddConfigItems(item.configs, MENUBAR_MENU_ITEM_HEIGHT)
+
+  if isRTL() {
+    menu.ShowAt(item, 1.0, 1.0, menu.ChildrenWidth())
+  } else {
+    menu.ShowAt(item, 0.0, 1.0, menu.ChildrenWidth())
+  }
+}
+
+// the highlighted item already shows the focus
+func (e *MenuBar) onFocus(evt *Event) {
+}
+
+func (e *MenuBar) onBlur(evt *Event) {
+  e.deactivate(false)
+}
+
+func (e *MenuBar) onKeyPress(evt *Event) {
+  n := len(e.items)
+  if !e.active || n == 0 {
+    return
+  }
+
+  next, prev := "right", "left"
+  if isRTL() {
+    next, prev = prev, next
+  }
+
+  nextItem := (e.current + 1)%n
+  prevItem := (e.current - 1 + n)%n
+
+  if !e.menuVisible() {
+    switch evt.Key {
+    case next:
+      e.setCurrent(nextItem)
+    case prev:
+      e.setCurrent(prevItem)
+    case "down", "return", "space":
+      e.openMenu(e.current)
+      e.Root.Menu.SelectNext()
+    case "escape":
+      e.deactivate(true)
+    default:
+      return
+    }
+
+    evt.StopPropagation()
+
+    return
+  }
+
+  // deepest level
+  menu := e.Root.openMenu()
+
+  switch evt.Key {
+  case "down":
+    menu.SelectNext()
+  case "up":
+    menu.SelectPrev()
+  case next:
+    if item := menu.selectedItem(); item != nil && item.subMenu != nil {
+      item.openSubMenu()
+      menu.SubMenu().SelectNext()
+    } else {
+      e.openMenu(nextItem)
+      e.Root.Menu.SelectNext()
+    }
+  case prev:
+    if menu.level > 0 {
+      menu.Hide()
+    } else {
+      e.openMenu(prevItem)
+      e.Root.Menu.SelectNext()
+    }
+  case "return", "space":
+    menu.ClickSelected()
+
+    if !e.Root.Menu.Visible() {
+      e.deactivate(true)
+    }
+  case "escape":
+    // the bar stays active at the first level
+    menu.Hide()
+  default:
+    return
+  }
+
+  evt.StopPropagation()
+}
+
+func (e *MenuBar) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := maxWidth, e.height
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, h}, e.Z(maxZIndex))
+
+  // the items are placed from the right for RTL
+  x := 0
+  for _, item := range e.items {
+    iw, _ := calcPos(item, w - x, h, maxZIndex)
+
+    if isRTL() {
+      item.Translate(w - x - iw, 0)
+    } else {
+      item.Translate(x, 0)
+    }
+
+    x += iw
+  }
+
+  return e.InitRect(w, h)
+}
+
+// top-level menu of a MenuBar
+type menuBarItem struct {
+  ElementData
+
+  bar     *MenuBar
+  index   int
+  caption *Text
+  configs []MenuItemConfig
+}
+
+func newMenuBarItem(bar *MenuBar, index int, caption string, configs []MenuItemConfig) *menuBarItem {
+  e := &menuBarItem{
+    NewElementData(2, 0),
+    bar,
+    index,
+    NewSans(caption, 10),
+    configs,
+  }
+
+  e.appendChild(e.caption)
+
+  e.setTypesAndColor()
+
+  e.On("mousedown", e.onMouseDown)
+  e.On("mouseenter", e.onMouseEnter)
+  e.On("mousebuttonoutsidemenu", e.onMouseButtonOutsideMenu)
+
+  return e
+}
+
+func (e *menuBarItem) setTypesAndColor() {
+  c := e.Root.P1.Skin.BGColor()
+  e.caption.SetColor(BLACK)
+
+  if e.bar.current == e.index {
+    c = e.Root.P1.Skin.SelColor()
+    e.caption.SetColor(WHITE)
+  }
+
+  for _, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri, c)
+  }
+}
+
+func (e *menuBarItem) onMouseDown(evt *Event) {
+  if e.bar.menuVisible() && e.bar.current == e.index {
+    // the focus moves to the clicked element anyway
+    e.bar.deactivate(false)
+  } else {
+    e.bar.openMenu(e.index)
+  }
+}
+
+// switch menus while one is open
+func (e *menuBarItem) onMouseEnter(evt *Event) {
+  if e.bar.menuVisible() && e.bar.current != e.index {
+    e.bar.openMenu(e.index)
+  }
+}
+
+// clicks on the other items of the bar are handled by their mousedown
+func (e *menuBarItem) onMouseButtonOutsideMenu(evt *Event) {
+  if !e.bar.IsHit(evt.X, evt.Y) {
+    e.bar.deactivate(false)
+  }
+}
+
+func (e *menuBarItem) Cursor(x, y int) int {
+  return sdl.SYSTEM_CURSOR_HAND
+}
+
+func (e *menuBarItem) Show() {
+  e.setTypesAndColor()
+
+  e.ElementData.Show()
+}
+
+func (e *menuBarItem) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  tw, th := calcPos(e.caption, maxWidth, maxHeight, maxZIndex)
+
+  w := tw + 2*MENUBAR_ITEM_PADDING
+  h := maxHeight
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, h}, e.Z(maxZIndex))
+
+  e.caption.Translate(MENUBAR_ITEM_PADDING, (h - th)/2)
+
+  return e.InitRect(w, h)
+}
@@ -5,24 +5,58 @@ import (
 )
 
 //go:generate ./gen_element MenuItem "appendChild CalcDepth On Size Padding H W"
+//go:generate ./gen_element menuItemLead "CalcDepth appendChild"
+
+type MenuItemKind int
+
+const (
+  MENU_ITEM_PLAIN MenuItemKind = iota
+  MENU_ITEM_CHECK
+  MENU_ITEM_RADIO // consecutive radio items form a group
+  MENU_ITEM_SEPARATOR
+)
+
+const (
+  MENUITEM_LEAD_WIDTH   = 24
+  MENUITEM_HINT_SPACING = 20
+)
+
+var MENUITEM_HINT_COLOR = sdl.Color{0x70, 0x70, 0x70, 0xff}
 
 type MenuItemConfig struct {
   Caption  string
   Callback func()
   Width    int
+  SubMenu  []MenuItemConfig // the callback isn't used if set
+  Kind     MenuItemKind
+  Checked  *bool  // state of check and radio items, updated when the item is clicked
+  Icon     string // glyph name
+  Shortcut string // only a hint, the shortcut itself must be handled elsewhere
+  Disabled bool
+  Reason   string // why the item is disabled, shown instead of the shortcut when hovering
 }
 
-// a plain button
+// a plain button, optionally with a check mark or an icon before the caption, and a hint after it
 type MenuItem struct {
   ElementData
 
   menu    *Menu
+  lead    *menuItemLead
   caption *Caption
+  hint    *Text
+  arrow   *Icon // nil if there is no submenu
+
+  kind     MenuItemKind
+  checked  *bool
+  shortcut string
+  reason   string
+  leadW    int // 0 if no item in the menu has a check mark or icon
 
   // state
   selected bool
 
   onClick func()
+  subMenu func(m *Menu) // fills the submenu
 }
 
 func NewMenuItem(captionText string, callback func()) *MenuItem {
@@ -33,11 +67,22 @@ func NewMenuItem(captionText string, callback func()) *MenuItem {
   e := &MenuItem{
     NewElementData(2, 0),
     menu,
+    nil,
     caption,
+    NewSans("", 10),
+    nil,
+    MENU_ITEM_PLAIN,
+    nil,
+    "",
+    "",
+    0,
     false,
     callback,
+    nil,
   }
 
+  e.lead = newMenuItemLead(e)
+
   e.height = 30
   e.width = 200
 
@@ -46,8 +91,8 @@ func NewMenuItem(captionText string, callback func()) *MenuItem {
   e.On("mouseup",    e.onMouseClick)
   e.On("mouseleave", e.onMouseLeave)
   e.On("mouseenter", e.onMouseEnter)
-  
-  e.appendChild(NewHor(START, CENTER, 0).H(-1).A(caption))
+
+  e.appendChild(e.lead, caption, e.hint)
 
   return e
 }
@@ -59,18 +104,147 @@ func newMenuItemFromConfig(cfg MenuItemConfig) *MenuItem {
     item.W(cfg.Width)
   }
 
+  if len(cfg.SubMenu) > 0 {
+    subItems := cfg.SubMenu
+
+    item.SubMenu(func(m *Menu) {
+      m.AddConfigItems(subItems, item.height)
+    })
+  }
+
+  if cfg.Kind == MENU_ITEM_CHECK || cfg.Kind == MENU_ITEM_RADIO {
+    item.Checkable(cfg.Kind, cfg.Checked)
+  }
+
+  if cfg.Icon != "" {
+    item.Icon(cfg.Icon)
+  }
+
+  if cfg.Shortcut != "" {
+    item.Shortcut(cfg.Shortcut)
+  }
+
+  if cfg.Disabled {
+    item.DisabledReason(cfg.Reason)
+  }
+
   return item
 }
 
+// fn fills the submenu, which is opened when hovering the item, or with the right arrow key
+func (e *MenuItem) SubMenu(fn func(m *Menu)) *MenuItem {
+  if e.arrow == nil {
+    // pointing away from the caption
+    if isRTL() {
+      e.arrow = NewIcon("arrow-up-drop", 10).SetOrientation(VER)
+    } else {
+      e.arrow = NewIcon("arrow-down-drop", 10).SetOrientation(VER)
+    }
+
+    e.appendChild(e.arrow)
+
+    e.setTypesAndColor()
+  }
+
+  e.subMenu = fn
+
+  return e
+}
+
+// kind is MENU_ITEM_CHECK or MENU_ITEM_RADIO, checked is updated when the item is clicked
+func (e *MenuItem) Checkable(kind MenuItemKind, checked *bool) *MenuItem {
+  if kind != MENU_ITEM_CHECK && kind != MENU_ITEM_RADIO {
+    panic("expected MENU_ITEM_CHECK or MENU_ITEM_RADIO")
+  }
+
+  if checked == nil {
+    checked = new(bool)
+  }
+
+  e.kind = kind
+  e.checked = checked
+
+  e.lead.setTypesAndTCoords()
+
+  return e
+}
+
+func (e *MenuItem) Checked() bool {
+  return e.checked != nil && *e.checked
+}
+
+// glyph shown before the caption, not shown for check and radio items
+func (e *MenuItem) Icon(name string) *MenuItem {
+  e.lead.setIcon(NewIcon(name, 10))
+
+  return e
+}
+
+func (e *MenuItem) Shortcut(s string) *MenuItem {
+  e.shortcut = s
+
+  e.hint.SetContent(s)
+
+  return e
+}
+
+func (e *MenuItem) DisabledReason(reason string) *MenuItem {
+  e.reason = reason
+
+  e.Disable()
+
+  return e
+}
+
+func (e *MenuItem) Tooltip() string {
+  if !e.enabled {
+    return e.reason
+  } else {
+    return ""
+  }
+}
+
+func (e *MenuItem) hasLead() bool {
+  return e.kind != MENU_ITEM_PLAIN || e.lead.icon != nil
+}
+
+func (e *MenuItem) subMenuVisible() bool {
+  return e.subMenu != nil && e.menu.subMenuVisible() && e.menu.SubMenu().IsOwnedBy(e)
+}
+
+func (e *MenuItem) openSubMenu() {
+  if e.subMenuVisible() {
+    return
+  }
+
+  sub := e.menu.SubMenu()
+
+  sub.ClearChildren()
+
+  e.subMenu(sub)
+
+  sub.ShowBeside(e, sub.ChildrenWidth())
+}
+
 func (e *MenuItem) setTypesAndColor() {
   var c sdl.Color
 
   if e.selected && e.enabled {
     c = e.Root.P1.Skin.SelColor()
     e.caption.SetColor(sdl.Color{0xff, 0xff, 0xff, 0xff})
+    e.hint.SetColor(sdl.Color{0xff, 0xff, 0xff, 0xff})
   } else {
     c = e.Root.P1.Skin.BGColor()
     e.caption.SetColor(sdl.Color{0x00, 0x00, 0x00, 0xff})
+    e.hint.SetColor(MENUITEM_HINT_COLOR)
+  }
+
+  if e.arrow != nil {
+    e.arrow.SetColor(e.caption.main.color)
+  }
+
+  if e.lead.icon != nil {
+    e.lead.icon.SetColor(e.caption.main.color)
   }
 
   for _, tri := range e.p1Tris {
@@ -91,10 +265,29 @@ func (e *MenuItem) onMouseEnter(evt *Event) {
   e.menu.unselectOtherMenuItems(e)
 
   e.Select()
+
+  if e.subMenu != nil && e.enabled {
+    e.openSubMenu()
+  } else {
+    e.menu.hideSubMenu()
+  }
+
+  if !e.enabled && e.reason != "" {
+    e.hint.SetContent(e.reason)
+    e.Root.ForceElementPosDirty(e)
+  }
 }
 
+// stays selected while the mouse is in its submenu
 func (e *MenuItem) onMouseLeave(evt *Event) {
-  e.Unselect()
+  if !e.subMenuVisible() {
+    e.Unselect()
+  }
+
+  if !e.enabled && e.reason != "" {
+    e.hint.SetContent(e.shortcut)
+    e.Root.ForceElementPosDirty(e)
+  }
 }
 
 func (e *MenuItem) Select() {
@@ -110,6 +303,28 @@ func (e *MenuItem) Selected() bool {
 }
 
 func (e *MenuItem) onMouseClick(evt *Event) {
+  if !e.enabled {
+    return
+  }
+
+  if e.subMenu != nil {
+    e.openSubMenu()
+  } else {
+    e.click()
+  }
+}
+
+// check and radio items are updated before the callback is called
+func (e *MenuItem) click() {
+  switch e.kind {
+  case MENU_ITEM_CHECK:
+    *e.checked = !*e.checked
+  case MENU_ITEM_RADIO:
+    e.menu.checkRadioItem(e)
+  }
+
+  e.lead.setTypesAndTCoords()
+
   if e.onClick != nil {
     e.onClick()
   }
@@ -123,14 +338,47 @@ func (e *MenuItem) Cursor(x, y int) int {
   }
 }
 
+// lead, caption, and the hint and arrow at the end
 func (e *MenuItem) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   w := maxWidth
+  h := e.height
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, h}, e.Z(maxZIndex))
+
+  // x from the start, mirrored for RTL
+  place := func(el Element, x, cw, ch int) {
+    if isRTL() {
+      el.Translate(w - x - cw, (h - ch)/2)
+    } else {
+      el.Translate(x, (h - ch)/2)
+    }
+  }
+
+  start := e.padding[3]
+  end := w - e.padding[1]
+
+  if e.arrow != nil {
+    aw, ah := calcPos(e.arrow, end - start, h, maxZIndex)
+    end -= aw
+    place(e.arrow, end, aw, ah)
+    end -= MENUITEM_HINT_SPACING
+  }
+
+  hw, hh := calcPos(e.hint, end - start, h, maxZIndex)
+  if hw > 0 {
+    end -= hw
+    place(e.hint, end, hw, hh)
+    end -= MENUITEM_HINT_SPACING
+  }
 
-  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, e.height}, e.Z(maxZIndex))
+  calcPos(e.lead, e.leadW, h, maxZIndex)
+  place(e.lead, start, e.leadW, h)
+  start += e.leadW
 
-  e.CalcPosChildren(w, e.height, maxZIndex)
+  cw, ch := calcPos(e.caption, end - start, h, maxZIndex)
+  place(e.caption, start, cw, ch)
 
-  return e.InitRect(w, e.height)
+  return e.InitRect(w, h)
 }
 
 func (e *MenuItem) Hide() {
@@ -138,3 +386,102 @@ func (e *MenuItem) Hide() {
 
   e.ElementData.Hide()
 }
+
+// check mark, radio mark or icon of a MenuItem
+type menuItemLead struct {
+  ElementData
+
+  item *MenuItem
+  icon *Icon
+}
+
+func newMenuItemLead(item *MenuItem) *menuItemLead {
+  e := &menuItemLead{
+    NewElementData(2, 0),
+    item,
+    nil,
+  }
+
+  e.setTypesAndTCoords()
+
+  return e
+}
+
+func (e *menuItemLead) setIcon(icon *Icon) {
+  if e.icon != nil {
+    panic("icon already set")
+  }
+
+  e.icon = icon
+
+  e.appendChild(icon)
+
+  e.setTypesAndTCoords()
+}
+
+func (e *menuItemLead) markSize() int {
+  if e.item.kind == MENU_ITEM_RADIO {
+    return e.Root.P1.Skin.RadioSize()
+  } else {
+    return e.Root.P1.Skin.TickSize()
+  }
+}
+
+func (e *menuItemLead) setTypesAndTCoords() {
+  tri0 := e.p1Tris[0]
+  tri1 := e.p1Tris[1]
+
+  if !e.item.Checked() || !e.Visible() {
+    e.Root.P1.SetTriType(tri0, VTYPE_HIDDEN)
+    e.Root.P1.SetTriType(tri1, VTYPE_HIDDEN)
+  } else {
+    e.Root.P1.SetTriType(tri0, VTYPE_SKIN)
+    e.Root.P1.SetTriType(tri1, VTYPE_SKIN)
+
+    e.Root.P1.Color.Set4Const(tri0, 1.0, 1.0, 1.0, 1.0)
+    e.Root.P1.Color.Set4Const(tri1, 1.0, 1.0, 1.0, 1.0)
+
+    var x0, y0 int
+    if e.item.kind == MENU_ITEM_RADIO {
+      x0, y0 = e.Root.P1.Skin.RadioOnOrigin()
+    } else {
+      x0, y0 = e.Root.P1.Skin.TickOrigin()
+    }
+
+    s := e.markSize()
+
+    x := [4]int{x0, x0 + s, 0, 0}
+    y := [4]int{y0, y0 + s, 0, 0}
+
+    e.Root.P1.setQuadSkinCoords(tri0, tri1, 0, 0, x, y)
+  }
+
+  // the mark replaces the icon
+  if e.icon != nil && e.item.kind != MENU_ITEM_PLAIN {
+    e.icon.Hide()
+  }
+}
+
+func (e *menuItemLead) Show() {
+  e.ElementData.Show()
+
+  e.setTypesAndTCoords()
+}
+
+func (e *menuItemLead) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := maxWidth, maxHeight
+
+  s := e.markSize()
+  if w == 0 {
+    s = 0
+  }
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{(w - s)/2, (h - s)/2, s, s}, e.Z(maxZIndex))
+
+  if e.icon != nil {
+    iw, ih := calcPos(e.icon, w, h, maxZIndex)
+    e.icon.Translate((w - iw)/2, (h - ih)/2)
+  }
+
+  return e.InitRect(w, h)
+}
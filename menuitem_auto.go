@@ -4,6 +4,7 @@ func (e *MenuItem) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -27,7 +28,7 @@ func (e *MenuItem) On(name string, fn EventListener) *MenuItem {
 func (e *MenuItem) Size(w, h int) *MenuItem {
   e.width = w
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -48,19 +49,19 @@ func (e *MenuItem) Padding(p ...int) *MenuItem {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *MenuItem) H(h int) *MenuItem {
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *MenuItem) W(w int) *MenuItem {
   e.width = w
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -0,0 +1,147 @@
+package glui
+
+import (
+  "fmt"
+  "math"
+  "strconv"
+  "strings"
+)
+
+//go:generate ./gen_element NumberColumn "appendChild CalcDepth"
+
+const DEFAULT_NUMBER_FMT = "%g"
+
+// values can be any int or float type, or numeric strings
+type NumberColumn struct {
+  BasicColumn
+
+  format string // applied to the value converted to float64, or to an integer for integer verbs (eg. %d)
+  intVerb bool
+}
+
+func NewNumberColumn(caption string, format string) *NumberColumn {
+  if format == "" {
+    format = DEFAULT_NUMBER_FMT
+  }
+
+  e_ := NewBasicColumn(END)
+
+  if !validNumberFormat(format) {
+    panic("invalid number format \"" + format + "\"")
+  }
+
+  e := &NumberColumn{
+    *e_,
+    format,
+    strings.IndexByte("bdoxX", numberVerb(format)) >= 0,
+  }
+
+  e.self = e
+  e.font = DEFAULT_MONO
+  e.head, e.caption, e.arrow, e.prio = newHeadButton(caption)
+  e.head.OnClick(e.onClickSort)
+
+  return e
+}
+
+// the verb is the first letter after the last %
+func numberVerb(format string) byte {
+  i := strings.LastIndex(format, "%")
+  if i < 0 {
+    return 0
+  }
+
+  for j := i + 1; j < len(format); j++ {
+    c := format[j]
+    if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+      return c
+    }
+  }
+
+  return 0
+}
+
+// only integer and float verbs
+func validNumberFormat(format string) bool {
+  v := numberVerb(format)
+
+  return v != 0 && strings.IndexByte("bdoxXeEfFgG", v) >= 0
+}
+
+// integer types are kept as is, floats are rounded
+func toInteger(x interface{}) interface{} {
+  switch v := x.(type) {
+  case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+    return v
+  case string:
+    if res, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+      return res
+    }
+  }
+
+  return int64(math.Round(toFloat(x)))
+}
+
+func toFloat(x interface{}) float64 {
+  switch v := x.(type) {
+  case float64:
+    return v
+  case float32:
+    return float64(v)
+  case int:
+    return float64(v)
+  case int8:
+    return float64(v)
+  case int16:
+    return float64(v)
+  case int32:
+    return float64(v)
+  case int64:
+    return float64(v)
+  case uint:
+    return float64(v)
+  case uint8:
+    return float64(v)
+  case uint16:
+    return float64(v)
+  case uint32:
+    return float64(v)
+  case uint64:
+    return float64(v)
+  case string:
+    res, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+    if err != nil {
+      panic(err)
+    }
+
+    return res
+  default:
+    panic("value is not a number")
+  }
+}
+
+func (e *NumberColumn) Format(x interface{}) string {
+  if isEmptyCell(x) {
+    return ""
+  } else if e.intVerb {
+    return fmt.Sprintf(e.format, toInteger(x))
+  } else {
+    return fmt.Sprintf(e.format, toFloat(x))
+  }
+}
+
+func (e *NumberColumn) Less(a, b interface{}) bool {
+  if less, ok := lessEmptyCell(a, b); ok {
+    return less
+  }
+
+  return toFloat(a) < toFloat(b)
+}
+
+func (e *NumberColumn) Parse(s string) (interface{}, error) {
+  return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+func (e *NumberColumn) onClickSort() {
+  e.table.clickSort(e)
+}
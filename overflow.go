@@ -69,7 +69,7 @@ func (e *Overflow) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   if innerW > maxWidth - sbTrackSize {
     horSB.Show()
     horSB.SetSliderLength(int(float64(maxWidth - 3*sbTrackSize)*float64(maxWidth - sbTrackSize)/float64(innerW)))
-    horSB.CalcPos(maxWidth - sbTrackSize, sbTrackSize, maxZIndex)
+    calcPos(horSB, maxWidth - sbTrackSize, sbTrackSize, maxZIndex)
     horSB.Translate(0.0, maxHeight - sbTrackSize)
     pos := horSB.Pos()
     crop = true
@@ -82,7 +82,7 @@ func (e *Overflow) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   if innerH > maxHeight - sbTrackSize {
     verSB.Show()
     verSB.SetSliderLength(int(float64(maxHeight - 3*sbTrackSize)*float64(maxHeight - sbTrackSize)/float64(innerH)))
-    verSB.CalcPos(sbTrackSize, maxHeight - sbTrackSize, maxZIndex)
+    calcPos(verSB, sbTrackSize, maxHeight - sbTrackSize, maxZIndex)
     verSB.Translate(maxWidth - sbTrackSize, 0.0)
     crop = true
     pos := verSB.Pos()
@@ -11,6 +11,7 @@ func (e *Overflow) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -20,25 +21,26 @@ func (e *Overflow) A(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
 func (e *Overflow) Size(w, h int) *Overflow {
   e.width = w
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Overflow) H(h int) *Overflow {
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *Overflow) W(w int) *Overflow {
   e.width = w
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
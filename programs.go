@@ -43,6 +43,7 @@ type Programs struct {
   glyphPass_aColorLoc  uint32
   glyphPass_aTCoordLoc uint32
   glyphPass_uTexLoc    uint32
+  glyphPass_uScaleLoc  uint32
   glyphPass_texID      uint32
   glyphPass_texUnit    uint32
   glyphPass_aPosVAO     uint32
@@ -152,6 +153,7 @@ func (p *Programs) initPtrs(debug *os.File) {
   p.glyphPass_aColorLoc  = getGLAttribLocation(p.glyphPass, "aColor")
   p.glyphPass_aTCoordLoc = getGLAttribLocation(p.glyphPass, "aTCoord")
   p.glyphPass_uTexLoc    = getGLUniformLocation(p.glyphPass, "uTex")
+  p.glyphPass_uScaleLoc  = getGLUniformLocation(p.glyphPass, "uScale")
   p.glyphPass_texID      = texIDs[1]
   p.glyphPass_texUnit    = gl.TEXTURE0
   p.glyphPass_aPosVAO    = vaos[5]
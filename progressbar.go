@@ -0,0 +1,184 @@
+package glui
+
+import (
+  "math"
+  "strconv"
+)
+
+//go:generate ./gen_element ProgressBar "CalcDepth appendChild On Size"
+
+const (
+  PROGRESS_BLOCK_FRACTION = 0.3  // width of the moving block in indeterminate mode
+  PROGRESS_SWEEP_PERIOD   = 1600 // ms, for the block to move back and forth
+)
+
+// first 9 quads are the input-like border, the last quad is the bar
+// the caption shows the percentage
+type ProgressBar struct {
+  ElementData
+
+  caption *Text
+
+  value         float64 // between 0.0 and 1.0
+  showCaption   bool
+  indeterminate bool
+  phase         float64 // position of the block in indeterminate mode, between 0.0 and 1.0
+}
+
+func NewProgressBar() *ProgressBar {
+  e := &ProgressBar{
+    NewElementData(10*2, 0),
+    NewSans("0%", 10),
+    0.0,
+    false,
+    false,
+    0.0,
+  }
+
+  e.width, e.height = 200, 20
+
+  e.appendChild(e.caption)
+
+  e.setTypesAndTCoords()
+
+  e.caption.Hide()
+
+  return e
+}
+
+func (e *ProgressBar) borderT() int {
+  return e.Root.P1.Skin.InputBorderThickness()
+}
+
+func (e *ProgressBar) setTypesAndTCoords() {
+  e.Root.P1.setInputLikeElementTypesAndTCoords(e.p1Tris)
+
+  tri0 := e.p1Tris[18]
+  tri1 := e.p1Tris[19]
+
+  e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+  e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+
+  e.Root.P1.SetColorConst(tri0, e.Root.P1.Skin.SelColor())
+  e.Root.P1.SetColorConst(tri1, e.Root.P1.Skin.SelColor())
+}
+
+func (e *ProgressBar) Value() float64 {
+  return e.value
+}
+
+// v is clamped between 0.0 and 1.0
+func (e *ProgressBar) SetValue(v float64) {
+  if v < 0.0 {
+    v = 0.0
+  } else if v > 1.0 {
+    v = 1.0
+  }
+
+  e.value = v
+
+  // SetContent also shows the caption
+  e.caption.SetContent(strconv.Itoa(int(math.Round(v*100.0))) + "%")
+  e.syncCaption()
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *ProgressBar) ShowCaption(b bool) *ProgressBar {
+  e.showCaption = b
+
+  e.syncCaption()
+
+  return e
+}
+
+// in indeterminate mode a block moves back and forth, and the caption isn't shown
+func (e *ProgressBar) SetIndeterminate(b bool) *ProgressBar {
+  e.indeterminate = b
+  e.phase = 0.0
+
+  e.syncCaption()
+
+  e.Root.ForceElementPosDirty(e)
+
+  return e
+}
+
+func (e *ProgressBar) Indeterminate() bool {
+  return e.indeterminate
+}
+
+func (e *ProgressBar) syncCaption() {
+  if e.visible && e.showCaption && !e.indeterminate {
+    e.caption.Show()
+  } else {
+    e.caption.Hide()
+  }
+}
+
+func (e *ProgressBar) Show() {
+  e.setTypesAndTCoords()
+
+  e.ElementData.Show()
+
+  e.syncCaption()
+}
+
+func (e *ProgressBar) Animate(tick uint64) {
+  if e.indeterminate && e.visible {
+    periodTicks := uint64(PROGRESS_SWEEP_PERIOD/ANIMATION_LOOP_INTERVAL)
+
+    // triangle wave
+    t := float64(tick%periodTicks)/float64(periodTicks)
+    if t < 0.5 {
+      e.phase = 2.0*t
+    } else {
+      e.phase = 2.0 - 2.0*t
+    }
+
+    e.Root.ForceElementPosDirty(e)
+  }
+
+  e.ElementData.Animate(tick)
+}
+
+func (e *ProgressBar) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  if w > maxWidth {
+    w = maxWidth
+  }
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  t := e.borderT()
+
+  e.SetBorderedElementPos(w, h, t, maxZIndex)
+
+  // bar
+  inner := w - 2*t
+
+  x := 0
+  bw := int(math.Round(e.value*float64(inner)))
+  if e.indeterminate {
+    bw = int(math.Round(PROGRESS_BLOCK_FRACTION*float64(inner)))
+    x = int(math.Round(e.phase*float64(inner - bw)))
+  }
+
+  if isRTL() {
+    x = inner - x - bw
+  }
+
+  // between the background and the caption
+  z := e.Z(maxZIndex) - 0.5/float32(maxZIndex)
+
+  e.Root.P1.SetQuadPos(e.p1Tris[18], e.p1Tris[19], Rect{t + x, t, bw, h - 2*t}, z)
+
+  // caption
+  cw, ch := calcPos(e.caption, w, h, maxZIndex)
+  e.caption.Translate((w - cw)/2, (h - ch)/2)
+
+  return e.InitRect(w, h)
+}
@@ -0,0 +1,34 @@
+package glui
+func (e *ProgressBar) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *ProgressBar) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *ProgressBar) On(name string, fn EventListener) *ProgressBar {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
+func (e *ProgressBar) Size(w, h int) *ProgressBar {
+  e.width = w
+  e.height = h
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
@@ -0,0 +1,166 @@
+package glui
+
+import (
+  "fmt"
+  "math"
+  "strconv"
+  "strings"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element ProgressColumn "appendChild CalcDepth"
+//go:generate ./gen_element progressCell "CalcDepth"
+
+const (
+  PROGRESS_CELL_WIDTH  = 80 // if the column doesn't provide a width
+  PROGRESS_CELL_HEIGHT = 10
+)
+
+var PROGRESS_TRACK_COLOR = sdl.Color{0xd0, 0xd0, 0xd0, 0xff}
+
+// values are fractions between 0.0 and 1.0, shown as a bar
+type ProgressColumn struct {
+  BasicColumn
+}
+
+func NewProgressColumn(caption string) *ProgressColumn {
+  e_ := NewBasicColumn(CENTER)
+
+  e := &ProgressColumn{
+    *e_,
+  }
+
+  e.self = e
+  e.head, e.caption, e.arrow, e.prio = newHeadButton(caption)
+  e.head.OnClick(e.onClickSort)
+
+  return e
+}
+
+func toFraction(x interface{}) float64 {
+  f := toFloat(x)
+
+  if f < 0.0 {
+    return 0.0
+  } else if f > 1.0 {
+    return 1.0
+  } else {
+    return f
+  }
+}
+
+func (e *ProgressColumn) Format(x interface{}) string {
+  if isEmptyCell(x) {
+    return ""
+  }
+
+  return fmt.Sprintf("%d%%", int(math.Round(toFraction(x)*100.0)))
+}
+
+func (e *ProgressColumn) Less(a, b interface{}) bool {
+  if less, ok := lessEmptyCell(a, b); ok {
+    return less
+  }
+
+  return toFraction(a) < toFraction(b)
+}
+
+// accepts percentages (e.g. "50%") and fractions (e.g. "0.5")
+func (e *ProgressColumn) Parse(s string) (interface{}, error) {
+  s = strings.TrimSpace(s)
+
+  if strings.HasSuffix(s, "%") {
+    f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+    if err != nil {
+      return nil, err
+    }
+
+    return f/100.0, nil
+  } else {
+    return strconv.ParseFloat(s, 64)
+  }
+}
+
+func (e *ProgressColumn) newCell() Element {
+  return newProgressCell()
+}
+
+func (e *ProgressColumn) setCell(cell Element, x interface{}, selected bool) {
+  cell.(*progressCell).setFraction(toFraction(x), selected)
+}
+
+func (e *ProgressColumn) onClickSort() {
+  e.table.clickSort(e)
+}
+
+// first quad is the track, second quad is the bar
+type progressCell struct {
+  ElementData
+
+  fraction float64
+  selected bool
+}
+
+func newProgressCell() *progressCell {
+  e := &progressCell{
+    NewElementData(2*2, 0),
+    0.0,
+    false,
+  }
+
+  e.setTypesAndColors()
+
+  return e
+}
+
+func (e *progressCell) setTypesAndColors() {
+  barColor := e.Root.P1.Skin.SelColor()
+  if e.selected {
+    barColor = WHITE
+  }
+
+  for i, tri := range e.p1Tris {
+    e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+
+    if i < 2 {
+      e.Root.P1.SetColorConst(tri, PROGRESS_TRACK_COLOR)
+    } else {
+      e.Root.P1.SetColorConst(tri, barColor)
+    }
+  }
+}
+
+func (e *progressCell) setFraction(f float64, selected bool) {
+  if f != e.fraction || selected != e.selected {
+    e.fraction = f
+    e.selected = selected
+
+    e.setTypesAndColors()
+
+    e.Root.ForceElementPosDirty(e)
+  }
+}
+
+func (e *progressCell) Show() {
+  e.setTypesAndColors()
+
+  e.ElementData.Show()
+}
+
+func (e *progressCell) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w := maxWidth
+  if w < PROGRESS_CELL_WIDTH {
+    w = PROGRESS_CELL_WIDTH
+  }
+
+  h := PROGRESS_CELL_HEIGHT
+
+  // the track is behind the bar
+  zTrack := (normalizeZIndex(e.zIndex - 1, maxZIndex) + normalizeZIndex(e.zIndex, maxZIndex))*0.5
+
+  e.Root.P1.SetQuadPos(e.p1Tris[0], e.p1Tris[1], Rect{0, 0, w, h}, zTrack)
+  e.Root.P1.SetQuadPos(e.p1Tris[2], e.p1Tris[3], Rect{0, 0, int(e.fraction*float64(w)), h}, e.Z(maxZIndex))
+
+  return e.InitRect(w, h)
+}
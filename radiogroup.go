@@ -156,7 +156,7 @@ func (e *radioItem) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
 
   txt := e.text()
 
-  txtW, txtH := txt.CalcPos(maxWidth - x, maxHeight, maxZIndex)
+  txtW, txtH := calcPos(txt, maxWidth - x, maxHeight, maxZIndex)
 
   h := e.radioSize()
   dy := (h - txtH)/2
@@ -196,7 +196,7 @@ func (e *RadioGroup) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
     lineH := 0
     totalW := 0
     for _, child := range e.children {
-      cw, ch := child.CalcPos(maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
+      cw, ch := calcPos(child, maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
 
       if lineH == 0 {
         // first item of line
@@ -229,7 +229,7 @@ func (e *RadioGroup) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
     colW := 0
     totalH := 0
     for i, child := range e.children {
-      cw, ch := child.CalcPos(maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
+      cw, ch := calcPos(child, maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
 
       if colW == 0 {
         // first item of column
@@ -21,6 +21,7 @@ func (e *RadioGroup) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
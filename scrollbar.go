@@ -106,7 +106,9 @@ func (e *Scrollbar) SetSliderLength(sl int) {
 
   e.limitSliderPos()
 
-  e.Root.ForcePosDirty()
+  // the parent (e.g. Overflow) positions its content based on the slider
+  e.Root.ForceElementPosDirty(e)
+  e.Root.ForceElementPosDirty(e.parent)
 }
 
 func (e *Scrollbar) onKeyPress(evt *Event) {
@@ -211,8 +213,8 @@ func (e *Scrollbar) buttons() (*Button, *Button) {
 func (e *Scrollbar) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   b1, b2 := e.buttons()
 
-  b1.CalcPos(maxWidth, maxHeight, maxZIndex)
-  b2.CalcPos(maxWidth, maxHeight, maxZIndex)
+  calcPos(b1, maxWidth, maxHeight, maxZIndex)
+  calcPos(b2, maxWidth, maxHeight, maxZIndex)
 
   t := e.Root.P1.Skin.ButtonBorderThickness()
   dz := -0.5/float32(maxZIndex) // slider must be closer to viewer than track
@@ -384,7 +386,9 @@ func (e *Scrollbar) MoveTo(ss int) {
 
   e.limitSliderPos()
 
-  e.Root.ForcePosDirty()
+  // the parent (e.g. Overflow) positions its content based on the slider
+  e.Root.ForceElementPosDirty(e)
+  e.Root.ForceElementPosDirty(e.parent)
 }
 
 func (e *Scrollbar) Home() {
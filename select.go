@@ -1,6 +1,8 @@
 package glui
 
 import (
+  "strings"
+
   "github.com/veandco/go-sdl2/sdl"
 )
 
@@ -8,6 +10,8 @@ import (
 
 //go:generate ./gen_element SelectWrapper "CalcDepth"
 
+const SELECT_MAX_VISIBLE_OPTIONS = 10 // longer option lists scroll
+
 type SelectWrapper struct { // given to focusrect instead of actual wrapper
   ElementData
 
@@ -18,12 +22,19 @@ type Select struct {
   ElementData
 
   options []string
+  disabled []bool
+  icons   []string // glyph names, empty for no icon
   text   *Text
   arrow  *Icon
   wrapper *SelectWrapper
 
   value string
   onChange func(i int, value string)
+
+  // state
+  items         []*MenuItem // one per option while the menu is shown
+  typeAhead     string
+  typeAheadTick uint64
 }
 
 
@@ -31,11 +42,16 @@ func NewSelect(options []string) *Select {
   e := &Select{
     NewElementData(9*2, 0),
     options, 
+    make([]bool, len(options)),
+    make([]string, len(options)),
     NewSans("Choose animal", 10), 
     NewIcon("arrow-down-drop", 10),
     nil,
     "",
     nil,
+    nil,
+    "",
+    0,
   }
   
   e.wrapper = &SelectWrapper{NewElementData(0, 0), e}
@@ -52,6 +68,44 @@ func NewSelect(options []string) *Select {
   e.On("blur", e.onBlur)
   e.On("keydown", e.onKeyDown)
   e.On("keypress", e.onKeyPress)
+  e.On("textinput", e.onTextInput)
+
+  return e
+}
+
+// the value is cleared if it isn't one of the new options
+func (e *Select) SetOptions(options []string) {
+  e.options = options
+  e.disabled = make([]bool, len(options))
+  e.icons = make([]string, len(options))
+
+  if e.menuVisible() {
+    e.Root.Menu.Hide()
+  }
+
+  if e.value != "" && e.Index() == -1 {
+    e.SetValue("")
+  }
+}
+
+func (e *Select) Options() []string {
+  return e.options
+}
+
+// disabled options can't be chosen, but the current value can still be a disabled option
+func (e *Select) SetOptionDisabled(i int, b bool) *Select {
+  e.disabled[i] = b
+
+  return e
+}
+
+func (e *Select) OptionDisabled(i int) bool {
+  return e.disabled[i]
+}
+
+// glyph shown before the option in the menu
+func (e *Select) SetOptionIcon(i int, name string) *Select {
+  e.icons[i] = name
 
   return e
 }
@@ -120,28 +174,121 @@ func (e *Select) onKeyDown(evt *Event) {
       e.Root.Menu.Hide()
     } 
   } else if evt.Key == "down" {
-    i := e.Index()
-
-    if i == len(e.options) - 1 {
-      e.SetValue(e.options[0])
-    }  else {
-      e.SetValue(e.options[i+1])
+    if i := e.nextEnabled(e.Index(), 1); i != -1 {
+      e.SetValue(e.options[i])
     }
   } else if evt.Key == "up" {
-    i := e.Index()
-    
-    if i <= 0 {
-      e.SetValue(e.options[len(e.options)-1])
-    } else {
-      e.SetValue(e.options[i-1])
+    if i := e.nextEnabled(e.Index(), -1); i != -1 {
+      e.SetValue(e.options[i])
     }
   }
 }
 
+// wraps around, -1 if all options are disabled
+func (e *Select) nextEnabled(i int, d int) int {
+  n := len(e.options)
+
+  if i == -1 && d < 0 {
+    i = 0
+  }
+
+  for k := 0; k < n; k++ {
+    i = ((i + d)%n + n)%n
+
+    if !e.disabled[i] {
+      return i
+    }
+  }
+
+  return -1
+}
+
 func (e *Select) onKeyPress(evt *Event) {
   // TODO
 }
 
+// selects the next enabled option starting with the typed text, in the menu if it is shown
+func (e *Select) onTextInput(evt *Event) {
+  tick := e.Root.CurrentTick()
+
+  if tick - e.typeAheadTick > uint64(TYPE_AHEAD_TIMEOUT/ANIMATION_LOOP_INTERVAL) {
+    e.typeAhead = ""
+  }
+
+  // space opens the menu
+  if e.typeAhead == "" && strings.TrimSpace(evt.Value) == "" {
+    return
+  }
+
+  e.typeAheadTick = tick
+
+  prefix := strings.ToLower(e.typeAhead + evt.Value)
+
+  // typing the same character repeatedly cycles through the options starting with it
+  runes := []rune(prefix)
+  if len(runes) > 1 && strings.Trim(prefix, string(runes[0])) == "" {
+    if i := e.searchOption(string(runes[0]), true); i != -1 {
+      e.typeAhead = prefix
+      e.highlight(i)
+      return
+    }
+  }
+
+  if i := e.searchOption(prefix, false); i != -1 {
+    e.typeAhead = prefix
+    e.highlight(i)
+  }
+}
+
+// the selected menu item, or the value if the menu isn't shown
+func (e *Select) current() int {
+  if e.menuVisible() {
+    for i, item := range e.items {
+      if item.Selected() {
+        return i
+      }
+    }
+
+    return -1
+  } else {
+    return e.Index()
+  }
+}
+
+// first enabled option (starting at the current option, wrapping around) that starts with prefix, -1 if none
+func (e *Select) searchOption(prefix string, skipCurrent bool) int {
+  n := len(e.options)
+
+  start := e.current()
+  if start < 0 {
+    start = 0
+  } else if skipCurrent {
+    start++
+  }
+
+  for k := 0; k < n; k++ {
+    i := (start + k)%n
+
+    if !e.disabled[i] && strings.HasPrefix(strings.ToLower(e.options[i]), prefix) {
+      return i
+    }
+  }
+
+  return -1
+}
+
+func (e *Select) highlight(i int) {
+  if e.menuVisible() {
+    item := e.items[i]
+
+    e.Root.Menu.unselectOtherMenuItems(item)
+    item.Select()
+    e.Root.Menu.scrollToSelected()
+  } else {
+    e.SetValue(e.options[i])
+  }
+}
+
 func (e *Select) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   return e.SetButtonPos(maxWidth, maxHeight, maxZIndex)
 }
@@ -170,7 +317,7 @@ func (e *SelectWrapper) Rect() Rect {
       ddr.X, 
       ddr.Y, 
       ddr.W, 
-      ddr.H*(1 + len(e.sel.options)) + 2*e.Root.P1.Skin.ButtonBorderThickness(),
+      ddr.H + e.Root.Menu.Rect().H,
     }
   } else {
     return ddr
@@ -224,20 +371,35 @@ func (e *Select) onShowMenu() {
 
   menu.ClearChildren()
 
-  for _, option := range e.options {
+  e.items = make([]*MenuItem, len(e.options))
+
+  for i, option := range e.options {
     option_ := option
 
     item := NewMenuItem(option_, func() {
       e.SetValue(option_)
     }).H(e.height)
 
-    menu.AddItem(item, true, option_ == e.value)
+    if e.icons[i] != "" {
+      item.Icon(e.icons[i])
+    }
+
+    menu.AddItem(item, !e.disabled[i], option_ == e.value)
+
+    e.items[i] = item
   }
 
+  t := e.Root.P1.Skin.ButtonBorderThickness()
+
+  menu.MaxHeight(SELECT_MAX_VISIBLE_OPTIONS*e.height + 2*t)
+
   e.Root.Menu.ShowAt(
     e,
     0.0,
     1.0,
     e.rect.W,
   )
+
+  // the current value is shown in long lists
+  menu.scrollToSelected()
 }
@@ -4,6 +4,7 @@ func (e *Select) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -27,7 +28,7 @@ func (e *Select) On(name string, fn EventListener) *Select {
 func (e *Select) Size(w, h int) *Select {
   e.width = w
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -48,7 +49,7 @@ func (e *Select) Padding(p ...int) *Select {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
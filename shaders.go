@@ -174,6 +174,7 @@ in vec4  vColor;
 in vec2  vTCoord;
 
 uniform sampler2D uTex;
+uniform float uScale; // physical pixels per logical pixel
 
 layout(location = 0) out vec4 oColor;
 //out float gl_FragDepth;
@@ -214,7 +215,7 @@ void main() {
   if (t == GLYPH) {
     vec4 gData = texture(uTex, vTCoord);
 
-    float d = (gData.x - 0.5)*(vParam*255.0/D_PER_PX);
+    float d = (gData.x - 0.5)*(vParam*uScale*255.0/D_PER_PX);
     float a = gData.y*(0.25*PI);
 
     bool outside = d < 0.5;
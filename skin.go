@@ -28,6 +28,15 @@ type Skin interface {
   ScrollbarTrack() []byte // 1xn
 }
 
+// optional, a skin that can render its bitmaps at an integer multiple of the resolution, so they
+// stay sharp when the ui is scaled
+// every bitmap of the returned skin must be exactly scale times wider and higher
+type ScalableSkin interface {
+  Skin
+
+  Scaled(scale int) Skin
+}
+
 func calcSquareSkinSize(d []byte) int {
   sqrtN := math.Sqrt(float64(len(d)/4))
   if math.Mod(sqrtN, 1.0) != 0.0 {
@@ -21,6 +21,7 @@ var (
 )
 
 type ClassicSkin struct {
+  scale int // 0 is the same as 1
 }
 
 func setColor(d []byte, pixId int, r, g, b, a byte) {
@@ -112,6 +113,26 @@ func swapColor(d []byte, pixId0 int, pixId1 int) {
   d[pixId1*4+3] = a
 }
 
+// nearest neighbour, each pixel becomes a k x k block
+func upscaleBitmap(d []byte, w int, h int, k int) []byte {
+  if k <= 1 {
+    return d
+  }
+
+  res := make([]byte, len(d)*k*k)
+
+  for i := 0; i < w*k; i++ {
+    for j := 0; j < h*k; j++ {
+      src := (i/k)*h + j/k
+      dst := i*h*k + j
+
+      copy(res[dst*4:dst*4+4], d[src*4:src*4+4])
+    }
+  }
+
+  return res
+}
+
 // in place
 func flipX(d []byte, w int, h int) {
   for i := 0; i < w/2; i++ {
@@ -135,6 +156,18 @@ func flipY(d []byte, w int, h int) {
   }
 }
 
+func (s *ClassicSkin) Scaled(scale int) Skin {
+  return &ClassicSkin{scale}
+}
+
+func (s *ClassicSkin) k() int {
+  if s.scale < 1 {
+    return 1
+  } else {
+    return s.scale
+  }
+}
+
 func (s *ClassicSkin) BGColor() sdl.Color {
   return sdl.Color{0xc0, 0xc0, 0xc0, 255}
 }
@@ -207,14 +240,18 @@ func (s *ClassicSkin) twoPxOutsetBorder(c0 byte, c1 byte, c2 byte, c3 byte) []by
 }
 
 func (s *ClassicSkin) Button() []byte {
-  return s.twoPxOutsetBorder(0xff, 0xc0, 0x80, 0x00)
+  d := s.twoPxOutsetBorder(0xff, 0xc0, 0x80, 0x00)
+
+  return upscaleBitmap(d, 5, 5, s.k())
 }
 
 func (s *ClassicSkin) ButtonPressed() []byte {
   d := s.Button()
 
-  flipX(d, 5, 5)
-  flipY(d, 5, 5)
+  n := calcSquareSkinSize(d)
+
+  flipX(d, n, n)
+  flipY(d, n, n)
 
   return d
 }
@@ -224,13 +261,15 @@ func (s *ClassicSkin) Input() []byte {
 
   setColor5x5Gray(d, 2, 2, 0xff)
 
-  return d
+  return upscaleBitmap(d, 5, 5, s.k())
 }
 
 func (s *ClassicSkin) Focus() []byte {
   c := s.SelColor()
 
-  return s.twoPxOutsetColorBorder(c, c, c, c)
+  d := s.twoPxOutsetColorBorder(c, c, c, c)
+
+  return upscaleBitmap(d, 5, 5, s.k())
 }
 
 func (s *ClassicSkin) Inset() []byte {
@@ -244,7 +283,7 @@ func (s *ClassicSkin) Inset() []byte {
     }
   }
 
-  return d
+  return upscaleBitmap(d, 5, 5, s.k())
 }
 
 func (s *ClassicSkin) Corner() []byte {
@@ -279,7 +318,7 @@ func (s *ClassicSkin) Corner() []byte {
   setColor5x5Gray(d, 3, 2, c2)
   setColor5x5Gray(d, 4, 2, c3)
 
-  return d
+  return upscaleBitmap(d, 5, 5, s.k())
 }
 
 func (s *ClassicSkin) Bar() []byte {
@@ -301,7 +340,7 @@ func (s *ClassicSkin) Bar() []byte {
   setColor3x3Gray(d, 1, 2, c2)
   setColor3x3Gray(d, 2, 2, c2)
 
-  return d
+  return upscaleBitmap(d, 3, 3, s.k())
 }
 
 type radioCircle struct {
@@ -401,13 +440,13 @@ func avgByte(bs []byte, ws []float64) byte {
 }
 
 func (s *ClassicSkin) RadioOff() []byte {
-  nSide := RADIO_SIZE
+  nSide := RADIO_SIZE*s.k()
 
   d := make([]byte, nSide*nSide*4)
 
   c0 := &radioCircle{float64(nSide)/2.0}
-  c1 := &radioCircle{float64(nSide)/2.0 - 1.0} // one pixel smaller than outer
-  c2 := &radioCircle{float64(nSide)/2.0 - 2.0} // two pixels smamller than outer
+  c1 := &radioCircle{float64(nSide)/2.0 - 1.0*float64(s.k())} // one pixel smaller than outer
+  c2 := &radioCircle{float64(nSide)/2.0 - 2.0*float64(s.k())} // two pixels smamller than outer
 
   for i := 0; i < nSide; i++ {
     for j := 0; j < nSide; j++ {
@@ -446,13 +485,13 @@ func (s *ClassicSkin) RadioOff() []byte {
 }
 
 func (s *ClassicSkin) RadioOn() []byte {
-  nSide := RADIO_SIZE
+  nSide := RADIO_SIZE*s.k()
 
   d := make([]byte, nSide*nSide*4)
 
   c0 := &radioCircle{float64(nSide)/2.0}
-  c1 := &radioCircle{float64(nSide)/2.0 - 1.0} // one pixel smaller than outer
-  c2 := &radioCircle{float64(nSide)/2.0 - 2.0} // two pixels smamller than outer
+  c1 := &radioCircle{float64(nSide)/2.0 - 1.0*float64(s.k())} // one pixel smaller than outer
+  c2 := &radioCircle{float64(nSide)/2.0 - 2.0*float64(s.k())} // two pixels smamller than outer
   c3 := &radioCircle{RADIO_DOT_RADIUS*float64(nSide)/2.0}
 
   for i := 0; i < nSide; i++ {
@@ -993,7 +1032,7 @@ func (t *tickTri) coverage(x0, y0, x1, y1 float64) float64 {
 }
 
 func (s *ClassicSkin) Tick() []byte {
-  nSide := TICK_SIZE
+  nSide := TICK_SIZE*s.k()
   d := make([]byte, nSide*nSide*4)
 
   size := float64(nSide/2)
@@ -1079,5 +1118,5 @@ func (s *ClassicSkin) ScrollbarTrack() []byte {
     setColor(d, i, shade, shade, shade, 255)
   }
 
-  return d
+  return upscaleBitmap(d, 1, n, s.k())
 }
@@ -1,6 +1,7 @@
 package glui
 
 import (
+  "math"
   "unsafe"
 
   "github.com/go-gl/gl/v4.1-core/gl"
@@ -15,6 +16,9 @@ type SkinMap struct {
   width  int
   height int
 
+  scale  int // the texture is uploaded at this multiple of width and height
+  scaledPieces []skinPiece
+
   buttonX int
   buttonY int
   buttonT int
@@ -58,10 +62,20 @@ type SkinMap struct {
   tunit uint32
 }
 
+// skin bitmap rendered at SkinMap.scale, x, y, w and h are unscaled
+type skinPiece struct {
+  x int
+  y int
+  w int
+  h int
+  d []byte
+}
+
 func newSkinMap(s Skin) *SkinMap {
   d := &SkinMap{} // zero construct, because number of fields of SkinMap increases a lot
 
   d.skin = s
+  d.scale = 1
   d.genData(s)
 
   return d
@@ -174,6 +188,83 @@ func (sm *SkinMap) genScrollbarTrack(s Skin, tb *TextureBuilder) {
   sm.sbTrackSize = n
 }
 
+// called when the ui scale changes, the texture coordinates stay the same, only the uploaded texture
+// gets more pixels
+func (sm *SkinMap) setScale(scale float64) {
+  k := int(math.Ceil(scale))
+
+  ss, ok := sm.skin.(ScalableSkin)
+  if !ok || k < 1 {
+    k = 1
+  }
+
+  if k == sm.scale {
+    return
+  }
+
+  sm.scale = k
+  sm.scaledPieces = nil
+
+  if k > 1 {
+    sm.genScaledData(ss.Scaled(k))
+  }
+
+  sm.tb.dirty = true
+}
+
+func (sm *SkinMap) genScaledData(s Skin) {
+  addPiece := func(x, y, w, h int, d []byte) {
+    if len(d) != w*h*sm.scale*sm.scale*4 {
+      panic("scaled skin has inconsistent size")
+    }
+
+    sm.scaledPieces = append(sm.scaledPieces, skinPiece{x, y, w, h, d})
+  }
+
+  addBordered := func(x, y, t int, d []byte) {
+    addPiece(x, y, 2*t+1, 2*t+1, d)
+  }
+
+  addBordered(sm.buttonX, sm.buttonY, sm.buttonT, s.Button())
+  addBordered(sm.buttonPressedX, sm.buttonPressedY, sm.buttonT, s.ButtonPressed())
+  addBordered(sm.inputX, sm.inputY, sm.inputT, s.Input())
+  addBordered(sm.focusX, sm.focusY, sm.focusT, s.Focus())
+  addBordered(sm.insetX, sm.insetY, sm.buttonT, s.Inset())
+  addBordered(sm.cornerX, sm.cornerY, sm.buttonT, s.Corner())
+
+  addPiece(sm.barX, sm.barY, sm.barT, sm.barT, s.Bar())
+  addPiece(sm.radioOffX, sm.radioOffY, sm.radioSize, sm.radioSize, s.RadioOff())
+  addPiece(sm.radioOnX, sm.radioOnY, sm.radioSize, sm.radioSize, s.RadioOn())
+  addPiece(sm.tickX, sm.tickY, sm.tickSize, sm.tickSize, s.Tick())
+  addPiece(sm.sbTrackX, sm.sbTrackY, 1, sm.sbTrackSize, s.ScrollbarTrack())
+}
+
+// allocated images are magnified, the skin pieces are replaced by their scaled versions
+func (sm *SkinMap) genUploadData() ([]byte, int, int) {
+  k := sm.scale
+  if k <= 1 {
+    return sm.data, sm.width, sm.height
+  }
+
+  w := sm.width*k
+  h := sm.height*k
+
+  d := upscaleBitmap(sm.data, sm.width, sm.height, k)
+
+  for _, p := range sm.scaledPieces {
+    ph := p.h*k
+
+    for i := 0; i < p.w*k; i++ {
+      dst := ((p.x*k + i)*h + p.y*k)*4
+      src := i*ph*4
+
+      copy(d[dst:dst+ph*4], p.d[src:src+ph*4])
+    }
+  }
+
+  return d, w, h
+}
+
 func (sm *SkinMap) genBordered(d []byte, tb *TextureBuilder, checkT bool) (int, int, int) {
   var t int
 
@@ -203,9 +294,7 @@ func (s *SkinMap) initGL(uTexLoc uint32, texID uint32, texUnit uint32) {
   gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
   checkGLError()
 
-  // remember: transpose for some reason
-  gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(s.height), int32(s.width), 0, gl.RGBA, 
-    gl.UNSIGNED_BYTE, unsafe.Pointer(&(s.data[0])))
+  s.upload()
   checkGLError()
 
   gl.BindTexture(gl.TEXTURE_2D, 0)
@@ -218,13 +307,19 @@ func (s *SkinMap) bind() {
   gl.BindTexture(gl.TEXTURE_2D, s.tid)
   if s.tb.dirty {
     s.syncTextureBuilder()
-    // remember: transpose for some reason
-    gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(s.height), int32(s.width), 0, gl.RGBA, 
-      gl.UNSIGNED_BYTE, unsafe.Pointer(&(s.data[0])))
+    s.upload()
   }
   checkGLError()
 }
 
+func (s *SkinMap) upload() {
+  d, w, h := s.genUploadData()
+
+  // remember: transpose for some reason
+  gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(h), int32(w), 0, gl.RGBA, 
+    gl.UNSIGNED_BYTE, unsafe.Pointer(&(d[0])))
+}
+
 func (s *SkinMap) ButtonOrigin() (int, int) {
   return s.buttonX, s.buttonY
 }
@@ -0,0 +1,432 @@
+package glui
+
+import (
+  "math"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+//go:generate ./gen_element Slider "CalcDepth On Size"
+
+const (
+  SLIDER_KNOB_LENGTH = 11 // along the track
+  SLIDER_KNOB_SIZE   = 20 // across the track
+  SLIDER_TICK_GAP    = 2
+  SLIDER_TICK_LENGTH = 4
+  SLIDER_DEFAULT_LENGTH = 150
+)
+
+var SLIDER_TICK_COLOR = sdl.Color{0x80, 0x80, 0x80, 0xff}
+
+// first 9 quads are the knob (styled like a button), the next 3 quads are the track (styled like the VSplit bar),
+//  the remaining quads are the tick marks
+// a vertical slider has its minimum at the bottom
+type Slider struct {
+  ElementData
+
+  orientation Orientation
+
+  min   float64
+  max   float64
+  step  float64 // 0 for continuous values
+  ticks float64 // interval between the tick marks, 0 for no tick marks
+  value float64
+
+  dragging      bool
+  lastDown      int // track position where the knob was grabbed
+  lastDownValue float64
+
+  onChange func(v float64)
+}
+
+func NewSlider(orientation Orientation) *Slider {
+  e := &Slider{
+    NewElementData(12*2, 0),
+    orientation,
+    0.0, 1.0, 0.0, 0.0, 0.0,
+    false, 0, 0.0,
+    nil,
+  }
+
+  if orientation == HOR {
+    e.width, e.height = SLIDER_DEFAULT_LENGTH, SLIDER_KNOB_SIZE
+  } else {
+    e.width, e.height = SLIDER_KNOB_SIZE, SLIDER_DEFAULT_LENGTH
+  }
+
+  e.setTypesAndTCoords()
+
+  e.On("mousedown", e.onMouseDown)
+  e.On("mousemove", e.onMouseMove)
+  e.On("mouseup",   e.onMouseUp)
+  e.On("focus", e.onFocus)
+  e.On("blur", e.onBlur)
+  e.On("keypress", e.onKeyPress)
+
+  return e
+}
+
+func (e *Slider) Range(min, max float64) *Slider {
+  if max < min {
+    panic("max smaller than min")
+  }
+
+  e.min = min
+  e.max = max
+
+  e.SetValue(e.value)
+
+  e.Root.ForceElementPosDirty(e)
+
+  return e
+}
+
+func (e *Slider) Step(s float64) *Slider {
+  e.step = s
+
+  e.SetValue(e.value)
+
+  return e
+}
+
+func (e *Slider) Ticks(interval float64) *Slider {
+  e.ticks = interval
+
+  e.Root.ForceElementPosDirty(e)
+
+  return e
+}
+
+func (e *Slider) OnChange(fn func(v float64)) *Slider {
+  e.onChange = fn
+
+  return e
+}
+
+func (e *Slider) Value() float64 {
+  return e.value
+}
+
+// v is clamped to the range and rounded to the step
+func (e *Slider) SetValue(v float64) {
+  if e.step > 0.0 {
+    v = e.min + math.Round((v - e.min)/e.step)*e.step
+  }
+
+  if v > e.max {
+    v = e.max
+  }
+
+  if v < e.min {
+    v = e.min
+  }
+
+  if v == e.value {
+    return
+  }
+
+  e.value = v
+
+  e.Root.ForceElementPosDirty(e)
+
+  if e.onChange != nil {
+    e.onChange(v)
+  }
+}
+
+func (e *Slider) pageStep() float64 {
+  if e.step > 0.0 {
+    return e.step*10.0
+  } else {
+    return (e.max - e.min)/10.0
+  }
+}
+
+func (e *Slider) lineStep() float64 {
+  if e.step > 0.0 {
+    return e.step
+  } else {
+    return (e.max - e.min)/100.0
+  }
+}
+
+func (e *Slider) setTypesAndTCoords() {
+  e.SetButtonStyle()
+
+  texX_, texY := e.Root.P1.Skin.getBarCoords()
+  texX := [4]int{texX_[0], texX_[1], 0, 0}
+
+  for j := 0; j < 3; j++ {
+    tri0 := e.p1Tris[(9 + j)*2 + 0]
+    tri1 := e.p1Tris[(9 + j)*2 + 1]
+
+    e.Root.P1.SetTriType(tri0, VTYPE_SKIN)
+    e.Root.P1.SetTriType(tri1, VTYPE_SKIN)
+    e.Root.P1.SetColorConst(tri0, sdl.Color{0xff, 0xff, 0xff, 0xff})
+    e.Root.P1.SetColorConst(tri1, sdl.Color{0xff, 0xff, 0xff, 0xff})
+
+    if e.orientation == HOR {
+      e.Root.P1.setQuadSkinCoordsT(tri0, tri1, 0, j, texX, texY)
+    } else {
+      e.Root.P1.setQuadSkinCoords(tri0, tri1, 0, j, texX, texY)
+    }
+  }
+}
+
+func (e *Slider) Show() {
+  e.setTypesAndTCoords()
+
+  e.ElementData.Show()
+}
+
+func (e *Slider) onFocus(evt *Event) {
+  if evt.IsKeyboardEvent() {
+    e.Root.FocusRect.Show(e)
+  }
+}
+
+func (e *Slider) onBlur(evt *Event) {
+  e.Root.FocusRect.Hide()
+}
+
+func (e *Slider) onKeyPress(evt *Event) {
+  if !e.enabled {
+    return
+  }
+
+  incr, decr := "right", "left"
+  if e.orientation == VER {
+    incr, decr = "up", "down"
+  } else if isRTL() {
+    incr, decr = decr, incr
+  }
+
+  switch evt.Key {
+  case incr:
+    e.SetValue(e.value + e.lineStep())
+  case decr:
+    e.SetValue(e.value - e.lineStep())
+  case "pageup":
+    e.SetValue(e.value + e.pageStep())
+  case "pagedown":
+    e.SetValue(e.value - e.pageStep())
+  case "home":
+    e.SetValue(e.min)
+  case "end":
+    e.SetValue(e.max)
+  }
+}
+
+func (e *Slider) length() int {
+  if e.orientation == HOR {
+    return e.rect.W
+  } else {
+    return e.rect.H
+  }
+}
+
+// distance the knob can travel
+func (e *Slider) travel() int {
+  t := e.length() - SLIDER_KNOB_LENGTH
+  if t < 1 {
+    return 1
+  }
+
+  return t
+}
+
+// from the start of the track, the start is on the left (right for RTL) for HOR, and at the bottom for VER
+func (e *Slider) fraction(v float64) float64 {
+  if e.max == e.min {
+    return 0.0
+  }
+
+  return (v - e.min)/(e.max - e.min)
+}
+
+// knob position relative to the top left corner of the slider
+func (e *Slider) knobPos() int {
+  p := int(math.Round(e.fraction(e.value)*float64(e.travel())))
+
+  if e.orientation == VER || isRTL() {
+    return e.travel() - p
+  } else {
+    return p
+  }
+}
+
+func (e *Slider) KnobRect() Rect {
+  if e.orientation == HOR {
+    return Rect{e.rect.X + e.knobPos(), e.rect.Y, SLIDER_KNOB_LENGTH, SLIDER_KNOB_SIZE}
+  } else {
+    return Rect{e.rect.X, e.rect.Y + e.knobPos(), SLIDER_KNOB_SIZE, SLIDER_KNOB_LENGTH}
+  }
+}
+
+// position of the center of the knob along the track, relative to the slider
+func (e *Slider) trackPos(evt *Event) int {
+  if e.orientation == HOR {
+    return evt.X - e.rect.X - SLIDER_KNOB_LENGTH/2
+  } else {
+    return evt.Y - e.rect.Y - SLIDER_KNOB_LENGTH/2
+  }
+}
+
+func (e *Slider) valueAt(p int) float64 {
+  if e.orientation == VER || isRTL() {
+    p = e.travel() - p
+  }
+
+  return e.min + float64(p)/float64(e.travel())*(e.max - e.min)
+}
+
+func (e *Slider) onMouseDown(evt *Event) {
+  if !e.enabled {
+    return
+  }
+
+  // clicking the track moves the center of the knob to the mouse
+  if !e.KnobRect().Hit(evt.X, evt.Y) {
+    e.SetValue(e.valueAt(e.trackPos(evt)))
+  }
+
+  e.dragging = true
+  e.lastDown = e.trackPos(evt)
+  e.lastDownValue = e.value
+}
+
+func (e *Slider) onMouseMove(evt *Event) {
+  if !e.dragging {
+    return
+  }
+
+  d := float64(e.trackPos(evt) - e.lastDown)/float64(e.travel())*(e.max - e.min)
+  if e.orientation == VER || isRTL() {
+    d = -d
+  }
+
+  e.SetValue(e.lastDownValue + d)
+}
+
+func (e *Slider) onMouseUp(evt *Event) {
+  if !e.dragging {
+    return
+  }
+
+  d := e.lastDown - e.trackPos(evt)
+  if d > 1 || d < -1 {
+    // don't trigger the click
+    evt.StopPropagation()
+  }
+
+  e.dragging = false
+}
+
+func (e *Slider) Cursor(x, y int) int {
+  if e.KnobRect().Hit(x, y) {
+    return e.ButtonCursor(x, y, e.enabled)
+  } else {
+    return -1
+  }
+}
+
+func (e *Slider) nTicks() int {
+  if e.ticks <= 0.0 || e.max == e.min {
+    return 0
+  }
+
+  return int(math.Floor((e.max - e.min)/e.ticks + 1e-9)) + 1
+}
+
+func (e *Slider) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  n := e.nTicks()
+  if n > 0 {
+    if e.orientation == HOR {
+      h = SLIDER_KNOB_SIZE + SLIDER_TICK_GAP + SLIDER_TICK_LENGTH
+    } else {
+      w = SLIDER_KNOB_SIZE + SLIDER_TICK_GAP + SLIDER_TICK_LENGTH
+    }
+  }
+
+  if w > maxWidth {
+    w = maxWidth
+  }
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  e.InitRect(w, h)
+
+  t := e.Root.P1.Skin.ButtonBorderThickness()
+  bt := e.Root.P1.Skin.BarThickness()
+  dz := -0.5/float32(maxZIndex) // knob must be closer to viewer than track
+  z := e.Z(maxZIndex)
+
+  // knob
+  p := e.knobPos()
+  if e.orientation == HOR {
+    e.SetBorderedElementPos(SLIDER_KNOB_LENGTH, SLIDER_KNOB_SIZE, t, maxZIndex)
+  } else {
+    e.SetBorderedElementPos(SLIDER_KNOB_SIZE, SLIDER_KNOB_LENGTH, t, maxZIndex)
+  }
+
+  for _, tri := range e.p1Tris[0:18] {
+    if e.orientation == HOR {
+      e.Root.P1.TranslateTri(tri, p, 0, dz)
+    } else {
+      e.Root.P1.TranslateTri(tri, 0, p, dz)
+    }
+  }
+
+  // track, from the center of the knob at one end to the center of the knob at the other end, with caps
+  dt := (bt - 1)/2
+  a := [4]int{
+    SLIDER_KNOB_LENGTH/2 - dt,
+    SLIDER_KNOB_LENGTH/2,
+    SLIDER_KNOB_LENGTH/2 + e.travel(),
+    SLIDER_KNOB_LENGTH/2 + e.travel() + dt + 1,
+  }
+  c := (SLIDER_KNOB_SIZE - bt)/2
+
+  for j := 0; j < 3; j++ {
+    tri0 := e.p1Tris[(9 + j)*2 + 0]
+    tri1 := e.p1Tris[(9 + j)*2 + 1]
+
+    if e.orientation == HOR {
+      e.Root.P1.SetQuadPos(tri0, tri1, Rect{a[j], c, a[j+1] - a[j], bt}, z)
+    } else {
+      e.Root.P1.SetQuadPos(tri0, tri1, Rect{c, a[j], bt, a[j+1] - a[j]}, z)
+    }
+  }
+
+  // tick marks
+  e.p1Tris = append(e.p1Tris[0:24], e.Root.P1.Resize(e.p1Tris[24:], n*2)...)
+
+  for i := 0; i < n; i++ {
+    tri0 := e.p1Tris[24 + i*2 + 0]
+    tri1 := e.p1Tris[24 + i*2 + 1]
+
+    e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+    e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+    e.Root.P1.SetColorConst(tri0, SLIDER_TICK_COLOR)
+    e.Root.P1.SetColorConst(tri1, SLIDER_TICK_COLOR)
+
+    tp := int(math.Round(e.fraction(e.min + float64(i)*e.ticks)*float64(e.travel())))
+    if e.orientation == VER || isRTL() {
+      tp = e.travel() - tp
+    }
+
+    tp += SLIDER_KNOB_LENGTH/2
+
+    if e.orientation == HOR {
+      e.Root.P1.SetQuadPos(tri0, tri1, Rect{tp, SLIDER_KNOB_SIZE + SLIDER_TICK_GAP, 1, SLIDER_TICK_LENGTH}, z)
+    } else {
+      e.Root.P1.SetQuadPos(tri0, tri1, Rect{SLIDER_KNOB_SIZE + SLIDER_TICK_GAP, tp, SLIDER_TICK_LENGTH, 1}, z)
+    }
+  }
+
+  return w, h
+}
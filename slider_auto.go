@@ -0,0 +1,25 @@
+package glui
+func (e *Slider) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *Slider) On(name string, fn EventListener) *Slider {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
+func (e *Slider) Size(w, h int) *Slider {
+  e.width = w
+  e.height = h
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
@@ -0,0 +1,350 @@
+package glui
+
+import (
+  "errors"
+  "math"
+  "os"
+  "strconv"
+  "strings"
+)
+
+//go:generate ./gen_element SpinBox "CalcDepth appendChild On Size"
+
+const (
+  SPINBOX_BUTTON_WIDTH = 16
+  SPINBOX_REPEAT_INTERVAL = 96 // ms, while a button is held down
+)
+
+// languages that use a decimal comma
+var DECIMAL_COMMA_LANGS = []string{
+  "bg", "ca", "cs", "da", "de", "el", "es", "et", "fi", "fr", "hr", "hu", "id", "is", "it", "lt", "lv", "nb", "nl",
+  "nn", "no", "pl", "pt", "ro", "ru", "sk", "sl", "sr", "sv", "tr", "uk", "vi",
+}
+
+// Input with up/down buttons, the value is only updated when Enter is pressed or when the input loses focus
+type SpinBox struct {
+  ElementData
+
+  input *Input
+  up    *Button
+  down  *Button
+
+  decimals int // 0 for integers
+  min      float64
+  max      float64
+  step     float64
+  value    float64
+
+  lastButtonTS   uint64
+  lastButtonIsUp bool
+
+  onChange func(v float64)
+}
+
+func NewSpinBox(decimals int) *SpinBox {
+  e := &SpinBox{
+    NewElementData(0, 0),
+    NewInput(),
+    NewIconButton("arrow-up-drop", 10, HOR),
+    NewIconButton("arrow-down-drop", 10, HOR),
+    decimals,
+    math.Inf(-1), math.Inf(1), 1.0, 0.0,
+    0, false,
+    nil,
+  }
+
+  e.width, e.height = 100, 30
+
+  e.appendChild(e.input, e.up, e.down)
+
+  // the input handles the keyboard, so the buttons aren't focusable
+  delete(e.up.evtListeners, "focus")
+  delete(e.down.evtListeners, "focus")
+
+  e.up.OnClick(func() {
+    e.stepBy(1.0)
+  })
+
+  e.down.OnClick(func() {
+    e.stepBy(-1.0)
+  })
+
+  e.up.On("mousedown", func(evt *Event) {
+    e.lastButtonTS = e.Root.CurrentTick()
+    e.lastButtonIsUp = true
+  })
+
+  e.up.On("mouseup", func(evt *Event) {
+    e.lastButtonTS = 0
+  })
+
+  e.down.On("mousedown", func(evt *Event) {
+    e.lastButtonTS = e.Root.CurrentTick()
+    e.lastButtonIsUp = false
+  })
+
+  e.down.On("mouseup", func(evt *Event) {
+    e.lastButtonTS = 0
+  })
+
+  e.input.On("blur", e.onBlurInput)
+
+  // bubble up from the input, after the input itself has handled them
+  e.On("keypress",  e.onKeyPress)
+  e.On("textinput", e.onTextInput)
+  e.On("wheel",     e.onWheel)
+
+  e.input.SetValue(e.format(e.value))
+
+  return e
+}
+
+func (e *SpinBox) Range(min, max float64) *SpinBox {
+  if max < min {
+    panic("max smaller than min")
+  }
+
+  e.min = min
+  e.max = max
+
+  e.SetValue(e.value)
+
+  return e
+}
+
+func (e *SpinBox) Step(s float64) *SpinBox {
+  e.step = s
+
+  return e
+}
+
+func (e *SpinBox) OnChange(fn func(v float64)) *SpinBox {
+  e.onChange = fn
+
+  return e
+}
+
+func (e *SpinBox) Value() float64 {
+  return e.value
+}
+
+// false if the text in the input can't be parsed or is out of range
+func (e *SpinBox) Valid() bool {
+  return !e.input.Invalid()
+}
+
+// v is clamped to the range, and rounded to the number of decimals
+func (e *SpinBox) SetValue(v float64) {
+  v = e.clamp(v)
+
+  changed := v != e.value
+
+  e.value = v
+
+  e.input.SetValue(e.format(v))
+  e.input.SetInvalid(false)
+
+  if changed && e.onChange != nil {
+    e.onChange(v)
+  }
+}
+
+func (e *SpinBox) clamp(v float64) float64 {
+  p := math.Pow(10.0, float64(e.decimals))
+  v = math.Round(v*p)/p
+
+  if v > e.max {
+    v = e.max
+  }
+
+  if v < e.min {
+    v = e.min
+  }
+
+  return v
+}
+
+func (e *SpinBox) format(v float64) string {
+  s := strconv.FormatFloat(v, 'f', e.decimals, 64)
+
+  if decimalSeparator() == ',' {
+    s = strings.Replace(s, ".", ",", 1)
+  }
+
+  return s
+}
+
+// the value in the input, or the last valid value
+func (e *SpinBox) current() float64 {
+  if v, err := parseNumber(e.input.Value()); err == nil {
+    return v
+  } else {
+    return e.value
+  }
+}
+
+func (e *SpinBox) stepBy(n float64) {
+  e.SetValue(e.current() + n*e.step)
+}
+
+// keeps the text if it can't be parsed, so it can be corrected
+func (e *SpinBox) commit() {
+  v, err := parseNumber(e.input.Value())
+  if err != nil {
+    e.input.SetInvalid(true)
+    return
+  }
+
+  e.SetValue(v)
+}
+
+func (e *SpinBox) validate() {
+  v, err := parseNumber(e.input.Value())
+
+  e.input.SetInvalid(err != nil || v < e.min || v > e.max)
+}
+
+func (e *SpinBox) onKeyPress(evt *Event) {
+  switch evt.Key {
+  case "up":
+    e.stepBy(1.0)
+  case "down":
+    e.stepBy(-1.0)
+  case "pageup":
+    e.stepBy(10.0)
+  case "pagedown":
+    e.stepBy(-10.0)
+  case "return":
+    e.commit()
+  default:
+    e.validate()
+  }
+}
+
+func (e *SpinBox) onTextInput(evt *Event) {
+  e.validate()
+}
+
+func (e *SpinBox) onBlurInput(evt *Event) {
+  e.commit()
+}
+
+func (e *SpinBox) onWheel(evt *Event) {
+  if evt.YRel < 0 {
+    e.stepBy(1.0)
+  } else if evt.YRel > 0 {
+    e.stepBy(-1.0)
+  }
+}
+
+func (e *SpinBox) Animate(tick uint64) {
+  if e.lastButtonTS != 0 {
+    delayTicks := uint64(MOUSE_DOWN_MOVE_DELAY/ANIMATION_LOOP_INTERVAL)
+    intervalTicks := uint64(SPINBOX_REPEAT_INTERVAL/ANIMATION_LOOP_INTERVAL)
+
+    if tick - e.lastButtonTS > delayTicks {
+      if (tick - e.lastButtonTS - delayTicks)%intervalTicks == 0 {
+        if e.lastButtonIsUp {
+          e.stepBy(1.0)
+        } else {
+          e.stepBy(-1.0)
+        }
+      }
+    }
+  }
+
+  e.ElementData.Animate(tick)
+}
+
+func (e *SpinBox) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  if w > maxWidth {
+    w = maxWidth
+  }
+
+  if h > maxHeight {
+    h = maxHeight
+  }
+
+  bw := SPINBOX_BUTTON_WIDTH
+  iw := w - bw
+
+  e.input.width, e.input.height = iw, h
+  calcPos(e.input, iw, h, maxZIndex)
+
+  e.up.width, e.up.height = bw, h/2
+  calcPos(e.up, bw, h/2, maxZIndex)
+
+  e.down.width, e.down.height = bw, h - h/2
+  calcPos(e.down, bw, h - h/2, maxZIndex)
+
+  // the buttons are on the left for RTL
+  if isRTL() {
+    e.input.Translate(bw, 0)
+    e.down.Translate(0, h/2)
+  } else {
+    e.up.Translate(iw, 0)
+    e.down.Translate(iw, h/2)
+  }
+
+  return e.InitRect(w, h)
+}
+
+// uses the locale of the environment (LC_ALL, LC_NUMERIC or LANG)
+func decimalSeparator() rune {
+  locale := ""
+  for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+    if v := os.Getenv(name); v != "" {
+      locale = v
+      break
+    }
+  }
+
+  fields := strings.FieldsFunc(locale, func(r rune) bool {
+    return r == '_' || r == '-' || r == '.' || r == '@'
+  })
+
+  if len(fields) == 0 {
+    return '.'
+  }
+
+  lang := strings.ToLower(fields[0])
+
+  for _, l := range DECIMAL_COMMA_LANGS {
+    if l == lang {
+      return ','
+    }
+  }
+
+  return '.'
+}
+
+// the group separator (the other one of '.' and ','), spaces and apostrophes are ignored
+func parseNumber(s string) (float64, error) {
+  sep := decimalSeparator()
+
+  s = strings.Map(func(r rune) rune {
+    switch {
+    case r == sep:
+      return '.'
+    case r == '.' || r == ',' || r == ' ' || r == '\u00a0' || r == '\'':
+      return -1
+    default:
+      return r
+    }
+  }, strings.TrimSpace(s))
+
+  if s == "" {
+    return 0.0, errors.New("empty")
+  }
+
+  v, err := strconv.ParseFloat(s, 64)
+  if err != nil {
+    return 0.0, err
+  } else if math.IsNaN(v) || math.IsInf(v, 0) {
+    return 0.0, errors.New("not a finite number")
+  }
+
+  return v, nil
+}
@@ -0,0 +1,109 @@
+package glui
+
+import (
+  "math"
+)
+
+//go:generate ./gen_element Spinner "CalcDepth On Size"
+
+const (
+  SPINNER_DOTS   = 8
+  SPINNER_PERIOD = 768 // ms per revolution
+)
+
+// busy indicator, a circle of dots with a brightness that rotates
+type Spinner struct {
+  ElementData
+
+  head int // brightest dot
+}
+
+func NewSpinner(size int) *Spinner {
+  e := &Spinner{
+    NewElementData(SPINNER_DOTS*2, 0),
+    0,
+  }
+
+  e.width, e.height = size, size
+
+  e.setTypesAndColors()
+
+  return e
+}
+
+func (e *Spinner) setTypesAndColors() {
+  c := e.Root.P1.Skin.SelColor()
+
+  for i := 0; i < SPINNER_DOTS; i++ {
+    // the dots behind the head fade out
+    age := (e.head - i + SPINNER_DOTS)%SPINNER_DOTS
+    c.A = uint8(0xff - age*0xff/SPINNER_DOTS)
+
+    tri0 := e.p1Tris[i*2 + 0]
+    tri1 := e.p1Tris[i*2 + 1]
+
+    e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+    e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+
+    e.Root.P1.SetColorConst(tri0, c)
+    e.Root.P1.SetColorConst(tri1, c)
+  }
+}
+
+func (e *Spinner) Show() {
+  e.setTypesAndColors()
+
+  e.ElementData.Show()
+}
+
+// only the colors change, so no repositioning is needed
+func (e *Spinner) Animate(tick uint64) {
+  if e.visible {
+    stepTicks := uint64(SPINNER_PERIOD/SPINNER_DOTS/ANIMATION_LOOP_INTERVAL)
+
+    head := int((tick/stepTicks)%SPINNER_DOTS)
+    if head != e.head {
+      e.head = head
+
+      e.setTypesAndColors()
+    }
+  }
+
+  e.ElementData.Animate(tick)
+}
+
+func (e *Spinner) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  w, h := e.GetSize()
+
+  size := w
+  if h < size {
+    size = h
+  }
+
+  if size > maxWidth {
+    size = maxWidth
+  }
+
+  if size > maxHeight {
+    size = maxHeight
+  }
+
+  d := size/5
+  if d < 1 {
+    d = 1
+  }
+
+  r := float64(size - d)/2.0
+
+  for i := 0; i < SPINNER_DOTS; i++ {
+    // clockwise, starting at the top
+    a := 2.0*math.Pi*float64(i)/float64(SPINNER_DOTS)
+
+    x := int(math.Round(r + r*math.Sin(a)))
+    y := int(math.Round(r - r*math.Cos(a)))
+
+    e.Root.P1.SetQuadPos(e.p1Tris[i*2], e.p1Tris[i*2 + 1], Rect{x, y, d, d}, e.Z(maxZIndex))
+  }
+
+  return e.InitRect(size, size)
+}
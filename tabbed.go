@@ -3,7 +3,9 @@ package glui
 import (
 )
 
-//go:generate ./gen_element Tabbed "CalcDepth appendChild"
+//go:generate ./gen_element Tabbed "CalcDepth appendChild On"
+
+const TABBED_BUTTON_SIZE = 30 // width of the scroll and tab list buttons, shown if the lips don't fit
 
 type Tabbed struct {
   ElementData
@@ -12,15 +14,48 @@ type Tabbed struct {
 
   lips []*tabLip
   tabs []*tabPage
+
+  prevButton *Button
+  nextButton *Button
+  listButton *Button
+
+  // state
+  scroll         int  // of the lips, 0 if they fit
+  scrollToActive bool // the active lip is scrolled into view by the next CalcPos
+  stripW         int  // visible width of the lips
 }
 
 func NewTabbed() *Tabbed {
-  return &Tabbed{
+  e := &Tabbed{
     NewElementData(0, 0),
     -1,
     []*tabLip{},
     []*tabPage{},
+    NewIconButton("arrow-up-drop", 10, VER),
+    NewIconButton("arrow-down-drop", 10, VER),
+    NewIconButton("arrow-down-drop", 10, HOR),
+    0,
+    false,
+    0,
   }
+
+  e.appendChild(e.prevButton, e.nextButton, e.listButton)
+
+  e.prevButton.OnClick(func() {
+    e.scrollLips(-e.stripW/2)
+  })
+
+  e.nextButton.OnClick(func() {
+    e.scrollLips(e.stripW/2)
+  })
+
+  e.listButton.OnClick(e.showTabList)
+
+  e.prevButton.Hide()
+  e.nextButton.Hide()
+  e.listButton.Hide()
+
+  return e
 }
 
 // returns a handle
@@ -44,6 +79,68 @@ func (e *Tabbed) NewTab(caption string, closeable bool) Container {
   return tab
 }
 
+func (e *Tabbed) Count() int {
+  return len(e.tabs)
+}
+
+// -1 if there are no tabs
+func (e *Tabbed) ActiveIndex() int {
+  return e.active
+}
+
+func (e *Tabbed) Tab(i int) Container {
+  return e.tabs[i]
+}
+
+// -1 if tab isn't a handle returned by NewTab()
+func (e *Tabbed) TabIndex(tab Container) int {
+  for i, t := range e.tabs {
+    if Container(t) == tab {
+      return i
+    }
+  }
+
+  return -1
+}
+
+func (e *Tabbed) Caption(i int) string {
+  return e.lips[i].caption.Value()
+}
+
+func (e *Tabbed) SelectTab(i int) {
+  if i < 0 || i > len(e.tabs) - 1 {
+    panic("tab index out of range")
+  }
+
+  e.setActive(i)
+}
+
+// without triggering the "tabclose" event
+func (e *Tabbed) RemoveTab(i int) {
+  e.closeTab(e.lips[i])
+}
+
+// also used when dragging a lip
+func (e *Tabbed) MoveTab(from, to int) {
+  if from == to {
+    return
+  }
+
+  lip := e.lips[from]
+  tab := e.tabs[from]
+  activeLip := e.lips[e.active]
+
+  e.lips = append(e.lips[0:from], e.lips[from+1:]...)
+  e.tabs = append(e.tabs[0:from], e.tabs[from+1:]...)
+
+  e.lips = append(e.lips[0:to], append([]*tabLip{lip}, e.lips[to:]...)...)
+  e.tabs = append(e.tabs[0:to], append([]*tabPage{tab}, e.tabs[to:]...)...)
+
+  e.setActiveLip(activeLip)
+
+  e.Root.ForceElementPosDirty(e)
+}
+
 func (e *Tabbed) lipIndex(l *tabLip) int {
   for i, lip := range e.lips {
     if lip == l {
@@ -71,6 +168,8 @@ func (e *Tabbed) setActive(idx int) {
 
   e.active = idx
 
+  e.scrollToActive = true
+
   e.Show()
 }
 
@@ -94,6 +193,26 @@ func (e *Tabbed) isActiveTab(t *tabPage) bool {
   return e.tabIndex(t) == e.active
 }
 
+// next or previous tab, wrapping around
+func (e *Tabbed) cycle(d int) {
+  n := len(e.lips)
+  if n > 1 {
+    e.setActive(((e.active + d)%n + n)%n)
+  }
+}
+
+// the "tabclose" event can cancel the close, evt.Tab is the index of the tab
+func (e *Tabbed) requestClose(l *tabLip) {
+  evt := NewMouseEvent(-1, -1)
+  evt.Tab = e.lipIndex(l)
+
+  TriggerEvent(e, "tabclose", evt)
+
+  if !evt.Canceled() {
+    e.closeTab(l)
+  }
+}
+
 func (e *Tabbed) closeTab(l *tabLip) {
   i := e.lipIndex(l)
 
@@ -103,19 +222,59 @@ func (e *Tabbed) closeTab(l *tabLip) {
   e.lips = append(e.lips[0:i], e.lips[i+1:]...)
   e.tabs = append(e.tabs[0:i], e.tabs[i+1:]...)
 
-  if i > 0 {
-    i -= 1
-  } else if len(e.lips) == 0 {
-    i = -1
-  } else {
-    i += 1
+  // the active tab stays active, unless it is the one that is closed
+  active := e.active
+  if i < active {
+    active -= 1
+  } else if i == active {
+    if i > 0 {
+      active = i - 1
+    } else if len(e.lips) == 0 {
+      active = -1
+    } else {
+      active = 0
+    }
   }
 
-  if i > len(e.lips) - 1 {
-    i = len(e.lips) - 1
+  e.setActive(active)
+}
+
+func (e *Tabbed) scrollLips(d int) {
+  e.scroll += d
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// all the tabs as radio items in the frame menu
+func (e *Tabbed) showTabList() {
+  menu := e.Root.Menu
+
+  if menu.IsOwnedBy(e.listButton) {
+    menu.Hide()
+    return
   }
 
-  e.setActive(i)
+  items := make([]MenuItemConfig, len(e.lips))
+
+  for i, lip := range e.lips {
+    i_ := i
+    checked := i == e.active
+
+    items[i] = MenuItemConfig{
+      Caption: lip.caption.Value(),
+      Callback: func() {
+        e.setActive(i_)
+      },
+      Kind: MENU_ITEM_RADIO,
+      Checked: &checked,
+    }
+  }
+
+  menu.ClearChildren()
+
+  menu.AddConfigItems(items, 30)
+
+  menu.ShowAt(e.listButton, 1.0, 1.0, menu.ChildrenWidth())
 }
 
 func (e *Tabbed) Show() {
@@ -128,38 +287,100 @@ func (e *Tabbed) Show() {
   }
 }
 
+// the lips are scrolled and cropped if they don't fit, with buttons at the end
 func (e *Tabbed) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   maxLipH := 0
 
   x := e.padding[3]
   y := e.padding[0]
 
+  avail := maxWidth - e.padding[1] - e.padding[3]
+
   for _, lip := range e.lips {
-    w, h := lip.CalcPos(maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
+    w, h := calcPos(lip, avail, maxHeight - y - e.padding[2], maxZIndex)
 
     if h > maxLipH {
       maxLipH = h
     }
 
+    x += w
+  }
+
+  lipsW := x - e.padding[3]
+
+  overflow := lipsW > avail && len(e.lips) > 1
+
+  e.stripW = avail
+  if overflow {
+    e.stripW = avail - 3*TABBED_BUTTON_SIZE
+  }
+
+  if overflow && e.scrollToActive && e.active >= 0 {
+    ax := 0
+    for _, lip := range e.lips[0:e.active] {
+      ax += lip.width
+    }
+
+    aw := e.lips[e.active].width
+
+    if ax < e.scroll {
+      e.scroll = ax
+    } else if ax + aw > e.scroll + e.stripW {
+      e.scroll = ax + aw - e.stripW
+    }
+  }
+
+  e.scrollToActive = false
+
+  if !overflow || e.scroll < 0 {
+    e.scroll = 0
+  } else if e.scroll > lipsW - e.stripW {
+    e.scroll = lipsW - e.stripW
+  }
+
+  x = e.padding[3] - e.scroll
+
+  for _, lip := range e.lips {
     lip.Translate(x, y)
 
-    x += w
+    x += lip.width
   }
 
-  totalLipW := x + e.padding[1]
+  if overflow {
+    strip := Rect{e.padding[3], y, e.stripW, maxLipH}
+
+    for _, lip := range e.lips {
+      lip.Crop(strip)
+    }
+
+    bh := maxLipH - INACTIVE_TABLIP_DELTA - e.Root.P1.Skin.ButtonBorderThickness()
+
+    for i, button := range []*Button{e.prevButton, e.nextButton, e.listButton} {
+      button.Show()
+      button.width, button.height = TABBED_BUTTON_SIZE, bh
+      calcPos(button, TABBED_BUTTON_SIZE, bh, maxZIndex)
+      button.Translate(e.padding[3] + e.stripW + i*TABBED_BUTTON_SIZE, y + INACTIVE_TABLIP_DELTA)
+    }
+  } else {
+    e.prevButton.Hide()
+    e.nextButton.Hide()
+    e.listButton.Hide()
+  }
+
+  totalLipW := lipsW + e.padding[1] + e.padding[3]
 
   x = e.padding[3]
   y += maxLipH - e.Root.P1.Skin.ButtonBorderThickness()
 
   maxTabW, maxTabH := 0, 0
 
-  if totalLipW > maxWidth {
+  if !overflow && totalLipW > maxWidth {
     maxWidth = totalLipW
   }
 
   for i, tab := range e.tabs {
     if i == e.active {
-      tabW, tabH := tab.CalcPos(maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
+      tabW, tabH := calcPos(tab, maxWidth - x - e.padding[1], maxHeight - y - e.padding[2], maxZIndex)
 
       tab.Translate(x, y)
 
@@ -178,7 +399,7 @@ func (e *Tabbed) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
 
   if maxW > maxWidth {
     maxWidth = maxW
-  } 
+  }
 
   if maxH > maxHeight {
     maxHeight = maxH
@@ -188,7 +409,7 @@ func (e *Tabbed) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   iLast := len(e.lips) - 1
   if iLast >= 0 {
 
-    if e.tabs[iLast].Rect().Right() - e.lips[iLast].Rect().Right() < e.Root.P1.Skin.ButtonBorderThickness() {
+    if !overflow && e.tabs[iLast].Rect().Right() - e.lips[iLast].Rect().Right() < e.Root.P1.Skin.ButtonBorderThickness() {
       e.lips[iLast].touchesRightSide(true)
     } else {
       e.lips[iLast].touchesRightSide(false)
@@ -197,3 +418,16 @@ func (e *Tabbed) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
 
   return e.InitRect(maxWidth, maxHeight)
 }
+
+// the innermost Tabbed that contains el, nil if none
+func findTabbed(el Element) *Tabbed {
+  for elementNotNil(el) {
+    if tabbed, ok := el.(*Tabbed); ok {
+      return tabbed
+    }
+
+    el = el.Parent()
+  }
+
+  return nil
+}
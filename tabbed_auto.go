@@ -11,6 +11,17 @@ func (e *Tabbed) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *Tabbed) On(name string, fn EventListener) *Tabbed {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
   return e
 }
 
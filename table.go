@@ -11,22 +11,70 @@ type Table struct {
 
   head []*Button
   body *tableBody
+  sb   *Scrollbar
+  marker *columnMarker
   headFocusNoTab bool
   masterCol      int
 
-  currentSortCol Column
-  currentSortDir SortState
+  // dragging a head button reorders the columns
+  dragCol     Column
+  dragStartX  int
+  dragTo      int // display index where dragCol will be inserted
+  dragX       int // x of the marker, relative to the table
+  headDragged bool // suppresses the click that ends a drag
+
+  // in-place cell editing
+  editor  *Input
+  editCol Column // nil if not editing
+  editRow int    // in the model, so the edit stays on the same row when the view is resorted
+
+  // typing while the body has focus selects the first row that starts with the typed text
+  typeAhead     string
+  typeAheadTick uint64
+
+  model     TableModel
+  filter    func(row int) bool // row in the TableModel, nil for no filter
+  sliderPos int // slider position during the last CalcPos, a difference means the user scrolled
+
+  sortKeys  []SortKey // first key has the highest priority
+  sortShift bool      // shift was down when the last head button was activated
+
+  copyHeaders bool // Ctrl+C also copies the captions
+
+  rowMenu func(row int, m *Menu) // fills the frame menu when right-clicking a row
+
+  // dragging over the rows selects a range
+  bandStart   int // view row, -1 if not dragging
+  bandY       int // last mouse y, the rows scroll while it is above or below the body
+  bandDragged bool
+}
+
+type SortKey struct {
+  Column Column
+  Dir    SortState
 }
 
 // the table itself is styled like an input
 func NewTable() *Table {
   e := &Table{
-    NewElementData(9*2, 0), // after the first 18 tris come the sel tris
+    NewElementData(9*2, 0), // border, the sel tris are part of the body
     make([]*Button, 0),
     newTableBody(),
+    NewScrollbar(VER),
+    newColumnMarker(),
     false,
     -1,
-    nil, UNSORTED,
+    nil, 0, -1, 0, false,
+    NewInput(), nil, -1,
+    "", 0,
+    nil,
+    nil,
+    0,
+    make([]SortKey, 0),
+    false,
+    false,
+    nil,
+    -1, 0, false,
   }
 
   e.width, e.height = 400, 400
@@ -34,12 +82,24 @@ func NewTable() *Table {
   e.setTypesAndTCoords()
 
   e.On("click", e.onMouseClick)
+  e.On("wheel", e.onWheel)
+  e.On("rightclick", e.onRightClick)
+  e.On("doubleclick", e.onDoubleClick)
+  e.On("mousedown", e.onMouseDownBody)
+  e.On("mousemove", e.onMouseMoveBody)
+  e.On("mouseup",   e.onMouseUpBody)
+
+  e.appendChild(e.sb, e.marker, e.body, e.editor)
 
-  e.appendChild(e.body)
+  e.editor.Hide()
+  e.editor.On("keypress", e.onKeyEditor)
+  e.editor.On("tab",      e.onTabEditor)
+  e.editor.On("blur",     e.onBlurEditor)
 
   e.body.On("focus", e.onFocusBody)
   e.body.On("blur",  e.onBlurBody)
   e.body.On("keypress", e.onKeyBody)
+  e.body.On("textinput", e.onTextInputBody)
 
   return e
 }
@@ -50,6 +110,82 @@ func (e *Table) MasterColumn(mc int) *Table {
   return e
 }
 
+// the table only holds the view order and the selection, the data itself stays in the model
+func (e *Table) SetModel(m TableModel) *Table {
+  e.model = m
+
+  e.resetContentWidths()
+
+  m.OnChange(e.onModelChange)
+
+  if n, ok := m.(RowsChangeNotifier); ok {
+    n.OnRowsInserted(e.onRowsInserted)
+    n.OnRowsRemoved(e.onRowsRemoved)
+  }
+
+  e.onModelChange()
+
+  return e
+}
+
+func (e *Table) Model() TableModel {
+  return e.model
+}
+
+// models usually change row by row (e.g. AddRow), so the view order is only rebuilt once, by the next CalcPos
+//  or by the next method that needs it
+func (e *Table) onModelChange() {
+  e.body.dirty = true
+
+  // the edited row was removed
+  if e.editCol != nil && e.editRow >= e.model.NumRows() {
+    e.endEdit(true)
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// the selection and the edited row are kept on the same model rows
+func (e *Table) onRowsInserted(row, n int) {
+  // appending (e.g. AddRow) doesn't move any rows
+  if row + n == e.model.NumRows() {
+    return
+  }
+
+  e.body.shiftRows(row, n)
+
+  if e.editCol != nil && e.editRow >= row {
+    e.editRow += n
+  }
+}
+
+func (e *Table) onRowsRemoved(row, n int) {
+  e.body.removeRows(row, n)
+
+  e.resetContentWidths()
+
+  if e.editCol != nil && e.editRow >= row {
+    if e.editRow < row + n {
+      e.endEdit(true)
+    } else {
+      e.editRow -= n
+    }
+  }
+}
+
+// the widest cells might be gone
+func (e *Table) resetContentWidths() {
+  for i := 0; i < e.body.nColumns(); i++ {
+    e.body.getColumn(i).column().contentW = 0
+  }
+}
+
+func (e *Table) syncRows() {
+  if e.body.dirty {
+    e.applySort()
+  }
+}
+
 func (e *Table) LineHeight() int {
   return e.body.lineHeight()
 }
@@ -63,10 +199,14 @@ func (e *Table) borderT() int {
 }
 
 func (e *Table) NumSelected() int {
+  e.syncRows()
+
   return e.body.numSelected()
 }
 
 func (e *Table) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  e.syncRows()
+
   w, h := 400, 400 //e.GetSize()
 
   if w > maxWidth {
@@ -77,25 +217,67 @@ func (e *Table) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
     h = maxHeight
   }
 
-  e.body.CalcPos(w - 2*e.borderT(), h - 2*e.borderT() - e.LineHeight(), maxZIndex)
+  t := e.borderT()
+  bodyW := w - 2*t
+  bodyH := h - 2*t - e.LineHeight()
+  bodyX := t
+
+  if e.calcScrollbarPos(bodyH, maxZIndex) {
+    sbW := e.Root.P1.Skin.ScrollbarTrackSize()
+
+    bodyW -= sbW
+
+    if isRTL() {
+      e.sb.Translate(t, t + e.LineHeight())
+      bodyX += sbW
+    } else {
+      e.sb.Translate(w - t - sbW, t + e.LineHeight())
+    }
+  }
+
+  calcPos(e.body, bodyW, bodyH, maxZIndex)
 
-  e.body.Translate(e.borderT(), e.borderT() + e.LineHeight())
+  e.body.Translate(bodyX, t + e.LineHeight())
 
   nc := e.body.nColumns()
 
-  x := 0
+  // head buttons are placed above the columns (columns are placed in reverse order for rtl)
   for i := 0; i < nc; i++ {
-    cw := e.body.getColumn(i).Rect().W
+    c := e.body.getColumn(i)
+    if c.column().hidden {
+      continue
+    }
+
+    cr := c.Rect()
 
     hb := e.head[i]
 
-    hb.Size(cw, e.LineHeight())
+    hb.Size(cr.W, e.LineHeight())
+
+    calcPos(hb, cr.W, e.LineHeight(), maxZIndex)
+
+    hb.Translate(cr.X, e.borderT())
 
-    hb.CalcPos(cw, e.LineHeight(), maxZIndex)
+    // grip on the end border of the head
+    grip := c.column().grip
 
-    hb.Translate(e.borderT() + x, e.borderT())
+    calcPos(grip, COLUMN_GRIP_WIDTH, e.LineHeight(), maxZIndex)
 
-    x += cw
+    if isRTL() {
+      grip.Translate(cr.X - COLUMN_GRIP_WIDTH/2, e.borderT())
+    } else {
+      grip.Translate(cr.Right() - COLUMN_GRIP_WIDTH/2, e.borderT())
+    }
+  }
+
+  if e.marker.Visible() {
+    calcPos(e.marker, COLUMN_MARKER_WIDTH, h - 2*t, maxZIndex)
+
+    e.marker.Translate(e.dragX - COLUMN_MARKER_WIDTH/2, t)
+  }
+
+  if e.editCol != nil {
+    e.calcEditorPos(maxZIndex)
   }
 
   e.SetBorderedElementPos(w, h, e.borderT(), maxZIndex)
@@ -103,10 +285,78 @@ func (e *Table) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   return e.InitRect(w, h)
 }
 
+// returns false if all the rows fit, in which case the scrollbar is hidden
+func (e *Table) calcScrollbarPos(bodyH int, maxZIndex int) bool {
+  nRows := e.body.nRows()
+  nVisible := bodyH/e.LineHeight()
+
+  if nRows <= nVisible || nVisible <= 0 {
+    if e.sb.Visible() {
+      e.sb.Hide()
+    }
+
+    e.body.first = 0
+
+    return false
+  }
+
+  if !e.sb.Visible() {
+    e.sb.Show()
+  }
+
+  sbW := e.Root.P1.Skin.ScrollbarTrackSize()
+
+  trackLen := bodyH - 2*sbW
+  sliderLen := trackLen*nVisible/nRows
+  if sliderLen < sbW {
+    sliderLen = sbW
+  }
+
+  e.sb.sliderLength = sliderLen
+
+  maxFirst := nRows - nVisible
+  maxPos := trackLen - sliderLen
+
+  // the body keeps the first visible row (e.g. changed by keyboard navigation), unless the slider was moved
+  if e.sb.sliderPos != e.sliderPos && maxPos > 0 {
+    e.body.first = int(float64(e.sb.sliderPos)*float64(maxFirst)/float64(maxPos) + 0.5)
+  }
+
+  if e.body.first > maxFirst {
+    e.body.first = maxFirst
+  } else if e.body.first < 0 {
+    e.body.first = 0
+  }
+
+  e.sb.sliderPos = int(float64(e.body.first)*float64(maxPos)/float64(maxFirst) + 0.5)
+  e.sliderPos = e.sb.sliderPos
+
+  calcPos(e.sb, sbW, bodyH, maxZIndex)
+
+  return true
+}
+
 func (e *Table) Show() {
   e.body.syncSelection()
 
   e.ElementData.Show()
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    c := e.body.getColumn(i).column()
+
+    if c.hidden {
+      e.head[i].Hide()
+      c.grip.Hide()
+    }
+  }
+
+  if e.dragCol == nil {
+    e.marker.Hide()
+  }
+
+  if e.editCol == nil {
+    e.editor.Hide()
+  }
 }
 
 func (e *Table) onFocusBody(evt *Event) {
@@ -142,65 +392,168 @@ func (e *Table) onKeyHead(evt *Event) {
   }
 }
 
-// TODO: first two children should be scrollbars
 func (e *Table) A(children ...Column) *Table {
   for _, child := range children {
+    // index of the model column
+    child.column().col = e.body.nColumns()
+
     head := child.Head()
     e.head = append(e.head, head)
-    e.appendChild(head)
     e.body.appendChild(child)
+
+    grip := newColumnGrip(child)
+    child.column().grip = grip
+
+    e.appendChild(head, grip)
+
     head.On("focus", e.onFocusHead)
     head.On("blur", e.onBlurHead)
     head.On("keypress", e.onKeyHead)
+
+    c := child
+    head.On("mousedown", func(evt *Event) {
+      e.onMouseDownHead(c, evt)
+    })
+    head.On("mousemove", e.onMouseMoveHead)
+    head.On("mouseup",   e.onMouseUpHead)
+    head.On("keydown",   e.onKeyDownHead)
+    head.On("click",     e.onClickHead)
   }
 
-  // find the body element, and move to last place, which makes more sense from focus perspective
-  found := false
-  for i, child := range e.children {
-    if _, ok := child.(*tableBody); ok {
-      if i == 0 {
-        e.children = append(e.children[1:], child)
-      } else if i < len(e.children) - 1 {
-        e.children = append(e.children[0:i], e.children[i+1:]...)
-        e.children = append(e.children, child)
-      }
+  e.orderChildren()
 
-      found = true
-      break
-    }
+  return e
+}
+
+// heads and grips follow the display order of the columns, grips must come after the heads so they are hit first,
+//  the body is last (apart from the cell editor), which makes more sense from focus perspective
+func (e *Table) orderChildren() {
+  children := []Element{e.sb}
+
+  for _, head := range e.head {
+    children = append(children, head)
   }
 
-  if !found {
-    panic("no *tableBody child")
+  for i := 0; i < e.body.nColumns(); i++ {
+    children = append(children, e.body.getColumn(i).column().grip)
   }
 
-  return e
+  children = append(children, e.marker, e.body, e.editor)
+
+  e.children = children
+
+  e.Root.ForceElementDepthDirty(e)
 }
 
+// appends to the default RowsModel, which is created on first use
 func (e *Table) AddRow(data ...interface{}) *Table {
-  e.body.addRow(data...)
+  if e.model == nil {
+    e.SetModel(NewRowsModel(e.body.nColumns()))
+  }
+
+  rm, ok := e.model.(*RowsModel)
+  if !ok {
+    panic("AddRow requires a *RowsModel, use the TableModel directly instead")
+  }
+
+  rm.AddRow(data...)
 
   return e
 }
 
 func (e *Table) SortByColumn(c Column, dir SortState) {
-  e.currentSortCol = c
-  e.currentSortDir = dir
+  e.SortBy([]SortKey{SortKey{c, dir}})
+}
 
-  sort.Sort(e)
+// keys with UNSORTED are ignored, the sort is stable with respect to the model order,
+//  and is reapplied when the model changes
+func (e *Table) SortBy(keys []SortKey) {
+  e.sortKeys = make([]SortKey, 0, len(keys))
 
+  for _, key := range keys {
+    if key.Dir == ASCENDING || key.Dir == DESCENDING {
+      e.sortKeys = append(e.sortKeys, key)
+    }
+  }
+
+  e.applySort()
+
+  e.syncSortIndicators()
+
+  e.Root.ForceElementPosDirty(e.body)
+}
+
+func (e *Table) SortKeys() []SortKey {
+  return e.sortKeys
+}
+
+// starts from the model order, so rows with equal keys are always in the same order
+func (e *Table) applySort() {
+  e.body.syncModel(e.model, e.passesFilter)
+
+  if len(e.sortKeys) > 0 {
+    sort.Stable(e)
+  }
+}
+
+// the priority is only shown if there are multiple keys
+func (e *Table) syncSortIndicators() {
   for i := 0; i < e.body.nColumns(); i++ {
-    c_ := e.body.getColumn(i)
-    if c_ == c {
-      c_.SetSortState(dir)
-    } else {
-      c_.SetSortState(UNSORTED)
+    c := e.body.getColumn(i)
+
+    dir := UNSORTED
+    prio := 0
+
+    for k, key := range e.sortKeys {
+      if key.Column == c {
+        dir = key.Dir
+
+        if len(e.sortKeys) > 1 {
+          prio = k + 1
+        }
+      }
     }
+
+    c.SetSortState(dir)
+    c.column().setSortPriority(prio)
+  }
+}
+
+// shift+click adds a secondary key, or toggles the direction of an existing key
+func (e *Table) clickSort(c Column) {
+  if e.headDragged {
+    return
   }
 
-  if e.NumSelected() > 0 {
-    e.body.syncSelection()
+  if !e.sortShift {
+    e.SortByColumn(c, NextSortState(c.column().sortState))
+
+    return
   }
+
+  keys := make([]SortKey, 0, len(e.sortKeys) + 1)
+  found := false
+
+  for _, key := range e.sortKeys {
+    if key.Column == c {
+      found = true
+
+      if key.Dir == DESCENDING {
+        // third click removes the key
+        continue
+      }
+
+      key.Dir = NextSortState(key.Dir)
+    }
+
+    keys = append(keys, key)
+  }
+
+  if !found {
+    keys = append(keys, SortKey{c, ASCENDING})
+  }
+
+  e.SortBy(keys)
 }
 
 func (e *Table) Len() int {
@@ -208,51 +561,105 @@ func (e *Table) Len() int {
 }
 
 func (e *Table) Less(i, j int) bool {
-  if e.currentSortDir == ASCENDING {
-    return e.currentSortCol.Less(i, j)
-  } else {
-    return e.currentSortCol.Less(j, i)
+  for _, key := range e.sortKeys {
+    col := key.Column.column().col
+
+    a := e.model.Cell(e.body.order[i], col)
+    b := e.model.Cell(e.body.order[j], col)
+
+    if key.Column.Less(a, b) {
+      return key.Dir == ASCENDING
+    } else if key.Column.Less(b, a) {
+      return key.Dir == DESCENDING
+    }
   }
+
+  return false
 }
 
 func (e *Table) Swap(i, j int) {
   e.body.swap(i, j)
 }
 
+// i is the row in view order
 func (e *Table) Select(i int) {
+  e.syncRows()
+
   e.body.selectRow(i, false)
+
+  e.body.scrollTo(i)
+}
+
+// view row at absolute y
+func (e *Table) rowAt(y int) int {
+  y -= e.rect.Y
+
+  return ((y - e.borderT())/ e.LineHeight()) - 1 + e.body.first
 }
 
 func (e *Table) onMouseClick(evt *Event) {
-  _, y := evt.RelPos(e.Rect())
+  // ignore clicks on the head and on the scrollbar
+  if !e.body.Rect().Hit(evt.X, evt.Y) {
+    return
+  }
+
+  // the selection was already made by dragging
+  if e.bandDragged {
+    e.bandDragged = false
+    return
+  }
 
-  i := ((y - e.borderT())/ e.LineHeight()) - 1
+  i := e.rowAt(evt.Y)
 
   if !evt.Shift {
     e.body.selectRow(i, evt.Ctrl)
   } else {
-    prev := e.body.selPivot
-
-    e.body.selectRow(i, false)
-
-    // prev must be selected last, so it remains the pivot
-    if prev < i {
-      for i_ := i - 1; i_ >= prev; i_-- {
-        e.body.selectRow(i_, true)
-      }
-    } else if prev > i {
-      for i_ := i + 1; i_ <= prev; i_++ {
-        e.body.selectRow(i_, true)
-      }
-    }
+    e.body.selectRange(e.body.selPivot, i)
   }
 }
 
 func (e *Table) onKeyBody(evt *Event) {
+  left, right := "left", "right"
+  if isRTL() {
+    left, right = right, left
+  }
+
   switch evt.Key {
   case "down":
     e.body.selectNextRow(evt.Shift)
   case "up":
     e.body.selectPrevRow(evt.Shift)
+  case left:
+    e.body.moveFocusCol(-1)
+    return
+  case right:
+    e.body.moveFocusCol(1)
+    return
+  case "f2":
+    e.editFocusedCell(e.body.selPivot)
+    return
+  case "return":
+    e.activateRow(e.body.selPivot)
+    return
+  case "c":
+    if evt.Ctrl {
+      e.copySelection()
+    }
+    return
+  case "a":
+    if evt.Ctrl {
+      e.SelectAll()
+    }
+    return
+  default:
+    return
+  }
+
+  e.body.scrollTo(e.body.selPivot)
+}
+
+func (e *Table) onWheel(evt *Event) {
+  if e.sb.Visible() {
+    e.sb.MoveBy(evt.YRel)
   }
 }
@@ -11,6 +11,7 @@ func (e *Table) appendChild(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -27,7 +28,7 @@ func (e *Table) On(name string, fn EventListener) *Table {
 func (e *Table) Size(w, h int) *Table {
   e.width = w
   e.height = h
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -48,7 +49,7 @@ func (e *Table) Padding(p ...int) *Table {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
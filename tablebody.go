@@ -1,25 +1,39 @@
 package glui
 
 import (
-  "strconv"
 )
 
 //go:generate ./gen_element tableBody "CalcDepth appendChild On"
 
+// rows are in view order, which can differ from the model order due to sorting
 type tableBody struct {
   ElementData
 
+  order    []int // view row -> model row, rows that don't pass the filter are left out
   selState []bool
   selPivot int
   showSel  bool
+  focusCol Column // cell of the pivot row that is edited by F2, moved with left/right, nil until then
+
+  filteredSel map[int]bool // selected model rows that are hidden by the filter
+
+  dirty bool // the model changed, the view order is rebuilt before it is used again
+
+  first    int // first visible row
+  nVisible int // number of rows that fit, set during CalcPos
 }
 
 func newTableBody() *tableBody {
   return &tableBody{
     NewElementData(0, 0),
+    make([]int, 0),
     make([]bool, 0),
     -1,
     false,
+    nil,
+    make(map[int]bool),
+    false,
+    0, 0,
   }
 }
 
@@ -52,6 +66,21 @@ func (e *tableBody) nColumns() int {
   return e.nChildren()
 }
 
+// in display order
+func (e *tableBody) visibleColumns() []Column {
+  cols := make([]Column, 0)
+
+  for i := 0; i < e.nColumns(); i++ {
+    c := e.getColumn(i)
+
+    if !c.column().hidden {
+      cols = append(cols, c)
+    }
+  }
+
+  return cols
+}
+
 func (e *tableBody) numSelected() int {
   count := 0
 
@@ -65,71 +94,126 @@ func (e *tableBody) numSelected() int {
 }
 
 func (e *tableBody) nRows() int {
-  return len(e.selState)
+  return len(e.order)
 }
 
 func (e *tableBody) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   nc := e.nColumns()
   mc := e.table().masterCol
 
-  if mc < 0 || mc > nc - 1 {
-    // no masterColumn, each column is the same width
-    wpc := maxWidth/nc
+  e.nVisible = maxHeight/e.lineHeight()
+  e.clampFirst()
+
+  n := e.nRows() - e.first
+  if n > e.nVisible {
+    n = e.nVisible
+  }
+
+  cols := e.visibleColumns()
+
+  for _, c := range cols {
+    c.column().setRows(e.first, n)
+  }
+
+  // columns with a user width and (if there is a master column) the other non-master columns have a fixed width,
+  //  the rest share the remaining space
+  var master Column = nil
+  if mc >= 0 && mc < nc && !e.getColumn(mc).column().hidden {
+    master = e.getColumn(mc)
+  }
 
-    for i := 0; i < nc; i++ {
-      c := e.getColumn(i)
+  widths := make([]int, len(cols))
+  remWidth := maxWidth
+  shared := make([]int, 0)
 
-      c.CalcPos(wpc, maxHeight, maxZIndex)
+  for i, c := range cols {
+    if c.column().userWidth >= 0 || (master != nil && c != master) {
+      widths[i], _ = calcPos(c, 1, maxHeight, maxZIndex)
 
-      c.Translate(i*wpc, 0)
+      remWidth -= widths[i]
+    } else {
+      shared = append(shared, i)
     }
-  } else {
-    // masterColumn gets the remaining space
-    remWidth := maxWidth
+  }
 
-    colWidths := make([]int, nc)
+  if remWidth < 0 {
+    remWidth = 0
+  }
 
-    for i := 0; i < nc; i++ {
-      if i != mc {
-        c := e.getColumn(i)
+  for j, i := range shared {
+    w := remWidth/len(shared)
+    if j == len(shared) - 1 {
+      w = remWidth - (len(shared) - 1)*w
+    }
 
-        actColWidth, _ := c.CalcPos(1, maxHeight, maxZIndex)
+    widths[i], _ = calcPos(cols[i], w, maxHeight, maxZIndex)
+  }
 
-        colWidths[i] = actColWidth
+  totalW := 0
+  for _, colW := range widths {
+    totalW += colW
+  }
 
-        remWidth -= actColWidth
-      }
+  if totalW > maxWidth {
+    maxWidth = totalW
+  }
+
+  focusX, focusW := 0, -1
+
+  cumW := 0
+  for i, c := range cols {
+    x := cumW
+    if isRTL() {
+      x = maxWidth - cumW - widths[i]
     }
 
-    c := e.getColumn(mc)
+    c.Translate(x, 0)
 
-    actColWidth, _ := c.CalcPos(remWidth, maxHeight, maxZIndex)
+    if c == e.focusCol {
+      focusX, focusW = x, widths[i]
+    }
 
-    colWidths[mc] = actColWidth
+    cumW += widths[i]
+  }
 
-    maxWidth = 0 
-    cumW := 0
-    for i, colW := range colWidths {
-      maxWidth += colW
+  e.calcSelectionPos(maxWidth, n, focusX, focusW, maxZIndex)
 
-      if i > 0 {
-        c := e.getColumn(i)
+  return e.InitRect(maxWidth, maxHeight)
+}
 
-        c.Translate(cumW, 0)
+// only the visible selected rows get a quad
+// the focused cell of the pivot row gets an outline of four more quads (focusW < 0 if there is no focused cell)
+func (e *tableBody) calcSelectionPos(w int, n int, focusX, focusW int, maxZIndex int) {
+  numSel := 0
+  if e.showSel {
+    for i := e.first; i < e.first + n; i++ {
+      if e.selState[i] {
+        numSel++
       }
-
-      cumW += colW
     }
   }
 
+  showFocus := e.showSel && focusW >= 0 && e.selPivot >= e.first && e.selPivot < e.first + n
+
+  if showFocus {
+    e.p1Tris = e.Root.P1.Resize(e.p1Tris, numSel*2 + 8)
+  } else {
+    e.p1Tris = e.Root.P1.Resize(e.p1Tris, numSel*2)
+  }
 
   selI := 0
-  for i, selState := range e.selState {
-    if selState {
+  for i := e.first; i < e.first + n && selI < numSel; i++ {
+    if e.selState[i] {
       tri0 := e.p1Tris[selI*2]
       tri1 := e.p1Tris[selI*2+1]
 
-      r := Rect{0, i*e.lineHeight(), maxWidth, e.lineHeight()}
+      e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+      e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+
+      e.Root.P1.SetColorConst(tri0, e.Root.P1.Skin.SelColor())
+      e.Root.P1.SetColorConst(tri1, e.Root.P1.Skin.SelColor())
+
+      r := Rect{0, (i - e.first)*e.lineHeight(), w, e.lineHeight()}
       z := e.Z(maxZIndex - 1)
 
       e.Root.P1.SetQuadPos(tri0, tri1, r, z)
@@ -138,73 +222,203 @@ func (e *tableBody) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
     }
   }
 
-  return e.InitRect(maxWidth, maxHeight)
+  if showFocus {
+    y := (e.selPivot - e.first)*e.lineHeight()
+    h := e.lineHeight()
+
+    lines := []Rect{
+      Rect{focusX, y, focusW, 1},
+      Rect{focusX, y + h - 1, focusW, 1},
+      Rect{focusX, y, 1, h},
+      Rect{focusX + focusW - 1, y, 1, h},
+    }
+
+    for i, r := range lines {
+      tri0 := e.p1Tris[numSel*2 + i*2]
+      tri1 := e.p1Tris[numSel*2 + i*2 + 1]
+
+      e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
+      e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+
+      e.Root.P1.SetColorConst(tri0, e.Root.P1.Skin.BGColor())
+      e.Root.P1.SetColorConst(tri1, e.Root.P1.Skin.BGColor())
+
+      e.Root.P1.SetQuadPos(tri0, tri1, r, e.Z(maxZIndex - 1))
+    }
+  }
 }
 
-func (e *tableBody) addRow(data ...interface{}) {
-  if len(data) != e.nColumns() {
-    panic("expected " + strconv.Itoa(e.nColumns()) + " row entries, got " + strconv.Itoa(len(data)))
+// d is +1 or -1, in display order
+func (e *tableBody) moveFocusCol(d int) {
+  cols := e.visibleColumns()
+  if len(cols) == 0 {
+    return
+  }
+
+  i := -1
+  for j, c := range cols {
+    if c == e.focusCol {
+      i = j
+    }
   }
 
-  for i, x := range data {
-    c := e.getColumn(i)
+  if i < 0 {
+    i = 0
+  } else {
+    i += d
+  }
 
-    c.AddRow(x)
+  if i < 0 {
+    i = 0
+  } else if i > len(cols) - 1 {
+    i = len(cols) - 1
   }
 
-  e.selState = append(e.selState, false)
+  e.focusCol = cols[i]
+
+  e.syncSelection()
 }
 
-func (e *tableBody) syncSelection() {
-  numSel := e.numSelected()
-  e.p1Tris = e.Root.P1.Resize(e.p1Tris, numSel*2)
-  selI := 0
-  for i, selState := range e.selState {
-    if selState {
-      tri0 := e.p1Tris[selI*2]
-      tri1 := e.p1Tris[selI*2+1]
+func (e *tableBody) clampFirst() {
+  if e.first > e.nRows() - e.nVisible {
+    e.first = e.nRows() - e.nVisible
+  }
 
-      // set tri color
+  if e.first < 0 {
+    e.first = 0
+  }
+}
 
-      if e.showSel {
-        e.Root.P1.SetTriType(tri0, VTYPE_PLAIN)
-        e.Root.P1.SetTriType(tri1, VTYPE_PLAIN)
+// scroll so that view row i is visible
+func (e *tableBody) scrollTo(i int) {
+  if i < 0 {
+    return
+  }
 
-        e.Root.P1.SetColorConst(tri0, e.Root.P1.Skin.SelColor())
-        e.Root.P1.SetColorConst(tri1, e.Root.P1.Skin.SelColor())
+  if i < e.first {
+    e.first = i
+  } else if i >= e.first + e.nVisible {
+    e.first = i - e.nVisible + 1
+  }
 
-        for ic := 0; ic < e.nColumns(); ic++ {
-          c := e.getColumn(ic)
+  e.clampFirst()
 
-          c.Select(i, true)
-        }
-      } else {
-        e.Root.P1.SetTriType(tri0, VTYPE_HIDDEN)
-        e.Root.P1.SetTriType(tri1, VTYPE_HIDDEN)
+  e.Root.ForceElementPosDirty(e.Parent())
+}
+
+// rebuilds the view order after the model or the filter changed, selected rows stay selected (also when filtered out)
+func (e *tableBody) syncModel(model TableModel, pass func(row int) bool) {
+  selModel := e.filteredSel
+  for i, sel := range e.selState {
+    if sel {
+      selModel[e.order[i]] = true
+    }
+  }
+
+  pivotModel := -1
+  if e.selPivot >= 0 && e.selPivot < len(e.order) {
+    pivotModel = e.order[e.selPivot]
+  }
+
+  n := 0
+  if model != nil {
+    n = model.NumRows()
+  }
+
+  e.order = make([]int, 0, n)
+  e.selState = make([]bool, 0, n)
+  e.selPivot = -1
+  e.filteredSel = make(map[int]bool)
+  e.dirty = false
+
+  for i := 0; i < n; i++ {
+    if !pass(i) {
+      if selModel[i] {
+        e.filteredSel[i] = true
       }
 
-      selI++
+      continue
+    }
+
+    if i == pivotModel {
+      e.selPivot = len(e.order)
     }
+
+    e.order = append(e.order, i)
+    e.selState = append(e.selState, selModel[i])
   }
 
-  if !e.showSel {
-    for ic := 0; ic < e.nColumns(); ic++ {
-      c := e.getColumn(ic)
+  e.syncSelection()
+}
 
-      c.ClearSelection()
+// model rows from row onwards moved down by n, the view order itself is rebuilt later by syncModel
+func (e *tableBody) shiftRows(row, n int) {
+  for i, modelRow := range e.order {
+    if modelRow >= row {
+      e.order[i] = modelRow + n
     }
   }
+
+  filteredSel := make(map[int]bool)
+  for modelRow := range e.filteredSel {
+    if modelRow >= row {
+      filteredSel[modelRow + n] = true
+    } else {
+      filteredSel[modelRow] = true
+    }
+  }
+
+  e.filteredSel = filteredSel
 }
 
-func (e *tableBody) swap(i, j int) {
-  e.selState[i], e.selState[j] = e.selState[j], e.selState[i]
+// model rows row to row+n-1 were removed, the rows after them moved up
+func (e *tableBody) removeRows(row, n int) {
+  order := make([]int, 0, len(e.order))
+  selState := make([]bool, 0, len(e.selState))
+  selPivot := -1
+
+  for i, modelRow := range e.order {
+    if modelRow >= row && modelRow < row + n {
+      continue
+    } else if modelRow >= row + n {
+      modelRow -= n
+    }
+
+    if i == e.selPivot {
+      selPivot = len(order)
+    }
+
+    order = append(order, modelRow)
+    selState = append(selState, e.selState[i])
+  }
 
-  for ic := 0; ic < e.nColumns(); ic++ {
-    c := e.getColumn(ic)
+  e.order = order
+  e.selState = selState
+  e.selPivot = selPivot
 
-    c.Swap(i, j)
+  filteredSel := make(map[int]bool)
+  for modelRow := range e.filteredSel {
+    if modelRow >= row + n {
+      filteredSel[modelRow - n] = true
+    } else if modelRow < row {
+      filteredSel[modelRow] = true
+    }
   }
 
+  e.filteredSel = filteredSel
+
+  e.clampFirst()
+}
+
+// text colors and sel quads are updated during the next CalcPos
+func (e *tableBody) syncSelection() {
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *tableBody) swap(i, j int) {
+  e.order[i], e.order[j] = e.order[j], e.order[i]
+  e.selState[i], e.selState[j] = e.selState[j], e.selState[i]
+
   if e.selPivot == i {
     e.selPivot = j
   } else if e.selPivot == j {
@@ -221,26 +435,12 @@ func (e *tableBody) selectRow(i int, keepOld bool) {
     if keepOld {
       // deselect
       e.selState[i] = false
-
-      for ic := 0; ic < e.nColumns(); ic++ {
-        c := e.getColumn(ic)
-
-        c.Deselect(i)
-      }
-
     }  else {
       for is := 0; is < e.nRows(); is++ {
         e.selState[is] = is == i
       }
-
-      for ic := 0; ic < e.nColumns(); ic++ {
-        c := e.getColumn(ic)
-
-        c.Select(i, false)
-      }
     }
   } else {
-    // only select  one row for now
     for is := 0; is < e.nRows(); is++ {
       if is == i {
         e.selState[is] = true
@@ -248,13 +448,6 @@ func (e *tableBody) selectRow(i int, keepOld bool) {
         e.selState[is] = false
       }
     }
-
-    // broadcast to each column as wel, so text color can be inverted
-    for ic := 0; ic < e.nColumns(); ic++ {
-      c := e.getColumn(ic)
-
-      c.Select(i, keepOld)
-    }
   }
 
   // update selPivot
@@ -279,6 +472,33 @@ func (e *tableBody) selectRow(i int, keepOld bool) {
   e.syncSelection()
 }
 
+// selects the rows between the pivot and i, the pivot is selected last so it remains the pivot
+func (e *tableBody) selectRange(pivot int, i int) {
+  e.selectRow(i, false)
+
+  if pivot < i {
+    for i_ := i - 1; i_ >= pivot; i_-- {
+      e.selectRow(i_, true)
+    }
+  } else if pivot > i {
+    for i_ := i + 1; i_ <= pivot; i_++ {
+      e.selectRow(i_, true)
+    }
+  }
+}
+
+func (e *tableBody) selectAll() {
+  for i := 0; i < e.nRows(); i++ {
+    e.selState[i] = true
+  }
+
+  if e.selPivot < 0 && e.nRows() > 0 {
+    e.selPivot = 0
+  }
+
+  e.syncSelection()
+}
+
 func (e *tableBody) deselectRow(i int) {
   if i < 0 || i >= e.nRows() {
     return
@@ -287,13 +507,6 @@ func (e *tableBody) deselectRow(i int) {
   if e.selState[i] {
     e.selState[i] = false
 
-    // broadcast to each column as wel, so text color can be inverted
-    for ic := 0; ic < e.nColumns(); ic++ {
-      c := e.getColumn(ic)
-
-      c.Deselect(i)
-    }
-
     e.syncSelection()
   }
 }
@@ -0,0 +1,255 @@
+package glui
+
+import (
+)
+
+// an Input is placed over the cell being edited
+
+func (e *Table) editable(c Column) bool {
+  bc := c.column()
+
+  if !bc.editable || bc.hidden {
+    return false
+  }
+
+  if bc.onCommit != nil {
+    return true
+  }
+
+  _, ok := e.model.(EditableTableModel)
+
+  return ok
+}
+
+// bool cells are toggled by clicking them instead
+func (e *Table) editableWithEditor(c Column) bool {
+  if _, ok := c.(*BoolColumn); ok {
+    return false
+  }
+
+  return e.editable(c)
+}
+
+// row is in view order
+func (e *Table) EditCell(row int, c Column) {
+  e.syncRows()
+
+  if row < 0 || row >= e.body.nRows() || !e.editableWithEditor(c) {
+    return
+  }
+
+  e.editCol = c
+  e.editRow = e.body.order[row]
+  e.body.focusCol = c
+
+  if !e.body.selState[row] {
+    e.body.selectRow(row, false)
+  }
+
+  e.body.scrollTo(row)
+
+  e.editor.SetValue(c.Format(e.model.Cell(e.body.order[row], c.column().col)))
+  e.editor.selAll()
+
+  if !e.editor.Visible() {
+    e.editor.Show()
+  }
+
+  Focus(e.editor)
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// the first editable cell if no cell is focused (or if the focused cell isn't editable)
+func (e *Table) editFocusedCell(row int) {
+  if c := e.body.focusCol; c != nil && e.editableWithEditor(c) {
+    e.EditCell(row, c)
+    return
+  }
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    c := e.body.getColumn(i)
+
+    if e.editableWithEditor(c) {
+      e.EditCell(row, c)
+      return
+    }
+  }
+}
+
+// writes the editor value to the model, returns false if the value is invalid
+func (e *Table) commitEdit() bool {
+  x, err := e.editCol.Parse(e.editor.Value())
+  if err != nil {
+    return false
+  }
+
+  return e.setModelCell(e.editRow, e.editCol, x)
+}
+
+// row is in view order
+func (e *Table) setCell(row int, c Column, x interface{}) bool {
+  return e.setModelCell(e.body.order[row], c, x)
+}
+
+// returns false if the column validator rejects x
+func (e *Table) setModelCell(modelRow int, c Column, x interface{}) bool {
+  bc := c.column()
+
+  if bc.validator != nil {
+    if err := bc.validator(x); err != nil {
+      return false
+    }
+  }
+
+  if m, ok := e.model.(EditableTableModel); ok {
+    m.SetCell(modelRow, bc.col, x)
+  }
+
+  if bc.onCommit != nil {
+    bc.onCommit(modelRow, x)
+  }
+
+  return true
+}
+
+func (e *Table) endEdit(refocus bool) {
+  e.editCol = nil
+  e.editRow = -1
+
+  e.editor.Hide()
+
+  if refocus {
+    Focus(e.body)
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// the model might have been resorted after the commit, so the row is looked up again
+func (e *Table) viewRow(modelRow int) int {
+  for i, row := range e.body.order {
+    if row == modelRow {
+      return i
+    }
+  }
+
+  return -1
+}
+
+// moves to the next (or previous) editable cell in display order, wrapping to the next (or previous) row
+func (e *Table) editNext(forward bool) {
+  c := e.editCol
+  modelRow := e.editRow
+
+  if !e.commitEdit() {
+    return
+  }
+
+  e.syncRows()
+
+  row := e.viewRow(modelRow)
+  i := e.columnIndex(c)
+  nc := e.body.nColumns()
+
+  for row >= 0 && row < e.body.nRows() {
+    if forward {
+      i++
+      if i == nc {
+        i = 0
+        row++
+      }
+    } else {
+      i--
+      if i < 0 {
+        i = nc - 1
+        row--
+      }
+    }
+
+    if row >= 0 && row < e.body.nRows() && e.editableWithEditor(e.body.getColumn(i)) {
+      e.EditCell(row, e.body.getColumn(i))
+      return
+    }
+  }
+
+  e.endEdit(true)
+}
+
+func (e *Table) calcEditorPos(maxZIndex int) {
+  j := e.viewRow(e.editRow) - e.body.first
+
+  // the edited row was scrolled out of view (or filtered out)
+  if j < 0 || j >= e.body.nVisible {
+    if e.editor.Visible() {
+      e.editor.Hide()
+    }
+
+    return
+  } else if !e.editor.Visible() {
+    e.editor.Show()
+  }
+
+  cr := e.editCol.Rect()
+
+  e.editor.width, e.editor.height = cr.W, e.LineHeight()
+
+  calcPos(e.editor, cr.W, e.LineHeight(), maxZIndex)
+
+  e.editor.Translate(cr.X, e.borderT() + (j + 1)*e.LineHeight())
+}
+
+func (e *Table) onDoubleClick(evt *Event) {
+  // also bubbles up from the editor itself
+  if e.editCol != nil || !e.body.Rect().Hit(evt.X, evt.Y) {
+    return
+  }
+
+  row := e.rowAt(evt.Y)
+
+  for _, c := range e.body.visibleColumns() {
+    r := c.Rect()
+
+    if evt.X >= r.X && evt.X < r.Right() && e.editableWithEditor(c) {
+      e.EditCell(row, c)
+      return
+    }
+  }
+
+  // the row is only activated by double-clicking cells that can't be edited
+  if ancestorHasEvent(e, "rowactivate") {
+    e.activateRow(row)
+  }
+}
+
+func (e *Table) onKeyEditor(evt *Event) {
+  switch evt.Key {
+  case "return":
+    if e.commitEdit() {
+      e.endEdit(true)
+    }
+
+    evt.stopPropagation = true
+  case "escape":
+    e.endEdit(true)
+
+    evt.stopPropagation = true
+  }
+}
+
+func (e *Table) onTabEditor(evt *Event) {
+  if e.editCol != nil {
+    e.editNext(!evt.Shift)
+
+    evt.stopPropagation = true
+  }
+}
+
+// clicking elsewhere commits, or cancels if the value is invalid
+func (e *Table) onBlurEditor(evt *Event) {
+  if e.editCol != nil {
+    e.commitEdit()
+
+    e.endEdit(false)
+  }
+}
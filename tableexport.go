@@ -0,0 +1,215 @@
+package glui
+
+import (
+  "encoding/csv"
+  "errors"
+  "fmt"
+  "io"
+  "os"
+  "strings"
+
+  "github.com/veandco/go-sdl2/sdl"
+)
+
+// include the captions of the head buttons when copying rows to the clipboard
+func (e *Table) CopyHeaders(b bool) *Table {
+  e.copyHeaders = b
+
+  return e
+}
+
+func (e *Table) SelectAll() {
+  e.syncRows()
+
+  e.body.selectAll()
+}
+
+// tab separated, visible columns in display order, selected rows in view order
+func (e *Table) SelectedText(headers bool) string {
+  e.syncRows()
+
+  cols := e.body.visibleColumns()
+
+  var b strings.Builder
+
+  writeLine := func(fields []string) {
+    for i, field := range fields {
+      if i > 0 {
+        b.WriteString("\t")
+      }
+
+      // tabs and newlines would break the structure
+      b.WriteString(strings.Map(func(r rune) rune {
+        if r == '\t' || r == '\n' || r == '\r' {
+          return ' '
+        } else {
+          return r
+        }
+      }, field))
+    }
+
+    b.WriteString("\n")
+  }
+
+  if headers {
+    writeLine(e.captions(cols))
+  }
+
+  for i, sel := range e.body.selState {
+    if sel {
+      writeLine(e.rowFields(i, cols))
+    }
+  }
+
+  return b.String()
+}
+
+func (e *Table) copySelection() {
+  if e.NumSelected() == 0 {
+    return
+  }
+
+  if err := sdl.SetClipboardText(e.SelectedText(e.copyHeaders)); err != nil {
+    fmt.Fprintf(os.Stderr, "failed to set clipboard text: %s\n", err.Error())
+  }
+}
+
+func (e *Table) captions(cols []Column) []string {
+  fields := make([]string, len(cols))
+
+  for i, c := range cols {
+    fields[i] = c.column().caption.Value()
+  }
+
+  return fields
+}
+
+// row in view order
+func (e *Table) rowFields(row int, cols []Column) []string {
+  fields := make([]string, len(cols))
+
+  for i, c := range cols {
+    fields[i] = c.Format(e.model.Cell(e.body.order[row], c.column().col))
+  }
+
+  return fields
+}
+
+// all columns in display order (also the hidden ones, so the result can be imported again), the rows as currently
+//  sorted and filtered, the first record contains the captions
+func (e *Table) ExportCSV(w io.Writer) error {
+  e.syncRows()
+
+  cols := make([]Column, e.body.nColumns())
+  for i := range cols {
+    cols[i] = e.body.getColumn(i)
+  }
+
+  cw := csv.NewWriter(w)
+
+  if err := cw.Write(e.captions(cols)); err != nil {
+    return err
+  }
+
+  for i := 0; i < e.body.nRows(); i++ {
+    if err := cw.Write(e.rowFields(i, cols)); err != nil {
+      return err
+    }
+  }
+
+  cw.Flush()
+
+  return cw.Error()
+}
+
+// appends rows to the default RowsModel, the first record must contain the captions of all the columns
+//  (in any order), the fields are parsed by the corresponding columns
+func (e *Table) ImportCSV(r io.Reader) error {
+  if e.model == nil {
+    e.SetModel(NewRowsModel(e.body.nColumns()))
+  }
+
+  rm, ok := e.model.(*RowsModel)
+  if !ok {
+    return errors.New("ImportCSV requires a *RowsModel")
+  }
+
+  cr := csv.NewReader(r)
+
+  header, err := cr.Read()
+  if err != nil {
+    return err
+  }
+
+  // column for each field
+  fieldCols := make([]Column, len(header))
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    c := e.body.getColumn(i)
+    caption := c.column().caption.Value()
+
+    found := false
+    for j, field := range header {
+      if field == caption && fieldCols[j] == nil {
+        fieldCols[j] = c
+        found = true
+        break
+      }
+    }
+
+    if !found {
+      return fmt.Errorf("missing column \"%s\"", caption)
+    }
+  }
+
+  rows := make([][]interface{}, 0)
+
+  // first line of the next record, quoted fields can span multiple lines (blank lines aren't counted though)
+  line := 1 + countNewlines(header)
+
+  for {
+    record, err := cr.Read()
+    if err == io.EOF {
+      break
+    } else if err != nil {
+      return err
+    }
+
+    line += 1
+
+    row := make([]interface{}, e.body.nColumns())
+
+    for j, field := range record {
+      c := fieldCols[j]
+      if c == nil {
+        // unknown columns are ignored
+        continue
+      }
+
+      x, err := c.Parse(field)
+      if err != nil {
+        return fmt.Errorf("line %d, column \"%s\": %s", line, header[j], err.Error())
+      }
+
+      row[c.column().col] = x
+    }
+
+    rows = append(rows, row)
+
+    line += countNewlines(record)
+  }
+
+  rm.AddRows(rows)
+
+  return nil
+}
+
+func countNewlines(record []string) int {
+  n := 0
+
+  for _, field := range record {
+    n += strings.Count(field, "\n")
+  }
+
+  return n
+}
@@ -0,0 +1,124 @@
+package glui
+
+import (
+  "strings"
+)
+
+const TYPE_AHEAD_TIMEOUT = 1000 // ms, typed text is forgotten after this pause
+
+// fn receives the row in the TableModel, rows for which fn returns false are hidden
+// selected rows that are hidden remain selected, and reappear when the filter is removed
+func (e *Table) SetFilter(fn func(row int) bool) {
+  e.filter = fn
+
+  e.onModelChange()
+}
+
+// hides the rows for which the formatted cell of c doesn't contain text (case insensitive), an empty text removes the filter
+func (e *Table) SetColumnFilter(c Column, text string) {
+  c.column().filter = strings.ToLower(text)
+
+  e.onModelChange()
+}
+
+func (e *Table) ClearFilters() {
+  e.filter = nil
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    e.body.getColumn(i).column().filter = ""
+  }
+
+  e.onModelChange()
+}
+
+func (e *Table) passesFilter(row int) bool {
+  if e.filter != nil && !e.filter(row) {
+    return false
+  }
+
+  for i := 0; i < e.body.nColumns(); i++ {
+    c := e.body.getColumn(i)
+    bc := c.column()
+
+    if bc.filter != "" {
+      content := strings.ToLower(c.Format(e.model.Cell(row, bc.col)))
+
+      if !strings.Contains(content, bc.filter) {
+        return false
+      }
+    }
+  }
+
+  return true
+}
+
+// the master column, or the first visible column if there is none
+func (e *Table) searchColumn() Column {
+  mc := e.masterCol
+
+  if mc >= 0 && mc < e.body.nColumns() && !e.body.getColumn(mc).column().hidden {
+    return e.body.getColumn(mc)
+  }
+
+  cols := e.body.visibleColumns()
+  if len(cols) == 0 {
+    return nil
+  } else {
+    return cols[0]
+  }
+}
+
+func (e *Table) onTextInputBody(evt *Event) {
+  tick := e.Root.CurrentTick()
+
+  if tick - e.typeAheadTick > uint64(TYPE_AHEAD_TIMEOUT/ANIMATION_LOOP_INTERVAL) {
+    e.typeAhead = ""
+  }
+
+  e.typeAheadTick = tick
+
+  prefix := strings.ToLower(e.typeAhead + evt.Value)
+
+  // typing the same character repeatedly cycles through the rows starting with it
+  runes := []rune(prefix)
+  if len(runes) > 1 && strings.Trim(prefix, string(runes[0])) == "" {
+    if e.searchRow(string(runes[0]), true) {
+      e.typeAhead = prefix
+      return
+    }
+  }
+
+  if e.searchRow(prefix, false) {
+    e.typeAhead = prefix
+  }
+}
+
+// selects the first row (starting at the pivot, wrapping around) whose search column starts with prefix
+func (e *Table) searchRow(prefix string, skipPivot bool) bool {
+  c := e.searchColumn()
+  n := e.body.nRows()
+
+  if c == nil || n == 0 {
+    return false
+  }
+
+  start := e.body.selPivot
+  if start < 0 {
+    start = 0
+  } else if skipPivot {
+    start++
+  }
+
+  for k := 0; k < n; k++ {
+    i := (start + k)%n
+
+    content := strings.ToLower(c.Format(e.model.Cell(e.body.order[i], c.column().col)))
+
+    if strings.HasPrefix(content, prefix) {
+      e.Select(i)
+      return true
+    }
+  }
+
+  return false
+}
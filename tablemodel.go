@@ -0,0 +1,142 @@
+package glui
+
+import (
+  "strconv"
+)
+
+// data source of a Table, the table only creates elements for the visible cells
+type TableModel interface {
+  NumRows() int
+  Cell(row, col int) interface{}
+
+  // the table registers a callback, which must be called by the model after its data changed
+  OnChange(fn func())
+}
+
+// cells of a Table with this model can be edited in place
+type EditableTableModel interface {
+  TableModel
+
+  SetCell(row, col int, x interface{})
+}
+
+// models that also report where rows were inserted or removed (before the OnChange callbacks are called),
+//  so the table can keep the selection on the same rows
+type RowsChangeNotifier interface {
+  OnRowsInserted(fn func(row, n int))
+  OnRowsRemoved(fn func(row, n int))
+}
+
+// default model, filled by Table.AddRow()
+type RowsModel struct {
+  nCols int
+  rows  [][]interface{}
+
+  listeners       []func()
+  insertListeners []func(row, n int)
+  removeListeners []func(row, n int)
+}
+
+func NewRowsModel(nCols int) *RowsModel {
+  return &RowsModel{nCols, make([][]interface{}, 0), make([]func(), 0), make([]func(int, int), 0), make([]func(int, int), 0)}
+}
+
+func (m *RowsModel) NumRows() int {
+  return len(m.rows)
+}
+
+func (m *RowsModel) Cell(row, col int) interface{} {
+  return m.rows[row][col]
+}
+
+func (m *RowsModel) OnChange(fn func()) {
+  m.listeners = append(m.listeners, fn)
+}
+
+func (m *RowsModel) OnRowsInserted(fn func(row, n int)) {
+  m.insertListeners = append(m.insertListeners, fn)
+}
+
+func (m *RowsModel) OnRowsRemoved(fn func(row, n int)) {
+  m.removeListeners = append(m.removeListeners, fn)
+}
+
+// notify the listeners
+func (m *RowsModel) Changed() {
+  for _, fn := range m.listeners {
+    fn()
+  }
+}
+
+func (m *RowsModel) rowsInserted(row, n int) {
+  for _, fn := range m.insertListeners {
+    fn(row, n)
+  }
+}
+
+func (m *RowsModel) rowsRemoved(row, n int) {
+  for _, fn := range m.removeListeners {
+    fn(row, n)
+  }
+}
+
+func (m *RowsModel) checkRow(data []interface{}) {
+  if len(data) != m.nCols {
+    panic("expected " + strconv.Itoa(m.nCols) + " row entries, got " + strconv.Itoa(len(data)))
+  }
+}
+
+func (m *RowsModel) AddRow(data ...interface{}) {
+  m.checkRow(data)
+
+  m.rows = append(m.rows, data)
+
+  m.rowsInserted(len(m.rows) - 1, 1)
+
+  m.Changed()
+}
+
+// listeners are only notified once
+func (m *RowsModel) AddRows(rows [][]interface{}) {
+  for _, data := range rows {
+    m.checkRow(data)
+  }
+
+  m.rows = append(m.rows, rows...)
+
+  m.rowsInserted(len(m.rows) - len(rows), len(rows))
+
+  m.Changed()
+}
+
+func (m *RowsModel) SetRow(row int, data ...interface{}) {
+  m.checkRow(data)
+
+  m.rows[row] = data
+
+  m.Changed()
+}
+
+func (m *RowsModel) SetCell(row, col int, x interface{}) {
+  m.rows[row][col] = x
+
+  m.Changed()
+}
+
+func (m *RowsModel) RemoveRow(row int) {
+  m.rows = append(m.rows[0:row], m.rows[row+1:]...)
+
+  m.rowsRemoved(row, 1)
+
+  m.Changed()
+}
+
+func (m *RowsModel) Clear() {
+  n := len(m.rows)
+
+  m.rows = make([][]interface{}, 0)
+
+  m.rowsRemoved(0, n)
+
+  m.Changed()
+}
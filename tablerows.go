@@ -0,0 +1,153 @@
+package glui
+
+const (
+  TABLE_AUTOSCROLL_INTERVAL = 64 // ms, one row is scrolled per interval while dragging past the edge of the body
+)
+
+// model rows, in view order
+func (e *Table) SelectedRows() []int {
+  e.syncRows()
+
+  rows := make([]int, 0)
+
+  for i, sel := range e.body.selState {
+    if sel {
+      rows = append(rows, e.body.order[i])
+    }
+  }
+
+  return rows
+}
+
+// fn adds the items for the right-clicked row (a model row) to the frame menu, the menu isn't shown if no items are added
+//  right-clicking an unselected row selects it first, so fn can also act on SelectedRows()
+func (e *Table) OnRowMenu(fn func(row int, m *Menu)) *Table {
+  e.rowMenu = fn
+
+  return e
+}
+
+// i in view order, triggers "rowactivate" with the model row in evt.Row
+func (e *Table) activateRow(i int) {
+  if i < 0 || i >= e.body.nRows() {
+    return
+  }
+
+  evt := NewMouseEvent(-1, -1)
+  evt.Row = e.body.order[i]
+
+  TriggerEvent(e, "rowactivate", evt)
+}
+
+func (e *Table) onRightClickBody(evt *Event) {
+  if !e.body.Rect().Hit(evt.X, evt.Y) {
+    return
+  }
+
+  i := e.rowAt(evt.Y)
+  if i < 0 || i >= e.body.nRows() {
+    return
+  }
+
+  if !e.body.selState[i] {
+    e.body.selectRow(i, false)
+  }
+
+  if e.rowMenu == nil {
+    return
+  }
+
+  e.Root.Menu.ClearChildren()
+
+  e.rowMenu(e.body.order[i], e.Root.Menu)
+
+  if e.Root.Menu.countMenuItems() == 0 {
+    return
+  }
+
+  e.Root.Menu.ShowAt(
+    e,
+    float64(evt.X - e.rect.X)/float64(e.rect.W),
+    float64(evt.Y - e.rect.Y)/float64(e.rect.H),
+    150,
+  )
+}
+
+func (e *Table) onMouseDownBody(evt *Event) {
+  e.bandStart = -1
+  e.bandDragged = false
+
+  // also bubbles up from the editor
+  if e.editCol != nil || !e.body.Rect().Hit(evt.X, evt.Y) {
+    return
+  }
+
+  i := e.rowAt(evt.Y)
+  if i < 0 || i >= e.body.nRows() {
+    return
+  }
+
+  e.bandStart = i
+  e.bandY = evt.Y
+}
+
+func (e *Table) onMouseMoveBody(evt *Event) {
+  if e.bandStart < 0 {
+    return
+  }
+
+  e.bandY = evt.Y
+
+  // rows outside the body are reached by auto scrolling
+  i := e.rowAt(evt.Y)
+  if i < e.body.first {
+    i = e.body.first
+  } else if i >= e.body.first + e.body.nVisible {
+    i = e.body.first + e.body.nVisible - 1
+  }
+
+  if i != e.bandStart {
+    e.bandDragged = true
+  }
+
+  if e.bandDragged {
+    e.dragSelectTo(i)
+  }
+}
+
+func (e *Table) onMouseUpBody(evt *Event) {
+  // bandDragged is reset by the click that follows
+  e.bandStart = -1
+}
+
+func (e *Table) dragSelectTo(i int) {
+  if i >= e.body.nRows() {
+    i = e.body.nRows() - 1
+  }
+
+  if i < 0 {
+    return
+  }
+
+  e.body.selectRange(e.bandStart, i)
+
+  e.body.scrollTo(i)
+}
+
+func (e *Table) Animate(tick uint64) {
+  if e.bandStart >= 0 && e.bandDragged {
+    intervalTicks := uint64(TABLE_AUTOSCROLL_INTERVAL/ANIMATION_LOOP_INTERVAL)
+
+    if tick%intervalTicks == 0 {
+      br := e.body.Rect()
+
+      if e.bandY < br.Y {
+        e.dragSelectTo(e.body.first - 1)
+      } else if e.bandY >= br.Bottom() {
+        e.dragSelectTo(e.body.first + e.body.nVisible)
+      }
+    }
+  }
+
+  e.ElementData.Animate(tick)
+}
@@ -11,6 +11,7 @@ const (
   TABLIP_CAPTION_SIZE   = 10
   TABLIP_CLOSE_INNER_SIZE = 15
   TABLIP_CLOSE_OUTER_SIZE = 20
+  TABLIP_DRAG_THRESHOLD = 5
 )
 
 type tabLipCaption struct {
@@ -26,7 +27,13 @@ type tabLip struct {
   tab     *tabPage
   caption *tabLipCaption
 
+  closeable bool
+
   touchesRightSide_ bool
+
+  // state
+  dragStartX int
+  dragging   bool // moved past TABLIP_DRAG_THRESHOLD while the mouse button is down
 }
 
 func newTabLip(tabbed *Tabbed, tab *tabPage, captionText string, closeable bool) *tabLip {
@@ -38,6 +45,9 @@ func newTabLip(tabbed *Tabbed, tab *tabPage, captionText string, closeable bool)
     tabbed,
     tab,
     caption,
+    closeable,
+    false,
+    -1,
     false,
   }
 
@@ -58,6 +68,9 @@ func newTabLip(tabbed *Tabbed, tab *tabPage, captionText string, closeable bool)
   }
 
   e.On("mousedown", e.onMouseDown)
+  e.On("mousemove", e.onMouseMove)
+  e.On("mouseup",   e.onMouseUp)
+  e.On("wheel",     e.onWheel)
   caption.On("focus", e.onFocusCaption)
   caption.On("blur", e.onBlurCaption)
   caption.On("keyup", e.onCaptionKeyUp)
@@ -73,6 +86,51 @@ func (e *tabLip) onMouseDown(evt *Event) {
   if !e.isActive() {
     e.tabbed.setActiveLip(e)
   }
+
+  e.dragStartX = evt.X
+  e.dragging = false
+}
+
+// the lip is moved as soon as the mouse passes the center of a neighbouring lip
+func (e *tabLip) onMouseMove(evt *Event) {
+  if e.dragStartX < 0 {
+    return
+  }
+
+  if !e.dragging {
+    dx := evt.X - e.dragStartX
+    if dx < TABLIP_DRAG_THRESHOLD && dx > -TABLIP_DRAG_THRESHOLD {
+      return
+    }
+
+    e.dragging = true
+  }
+
+  i := e.tabbed.lipIndex(e)
+
+  if i > 0 {
+    prev := e.tabbed.lips[i-1].Rect()
+    if evt.X < prev.X + prev.W/2 {
+      e.tabbed.MoveTab(i, i-1)
+      return
+    }
+  }
+
+  if i < len(e.tabbed.lips) - 1 {
+    next := e.tabbed.lips[i+1].Rect()
+    if evt.X > next.X + next.W/2 {
+      e.tabbed.MoveTab(i, i+1)
+    }
+  }
+}
+
+func (e *tabLip) onMouseUp(evt *Event) {
+  e.dragStartX = -1
+  e.dragging = false
+}
+
+func (e *tabLip) onWheel(evt *Event) {
+  e.tabbed.scrollLips(evt.YRel*MENU_SCROLL_SPEED)
 }
 
 func (e *tabLip) onClickCloseButton() {
@@ -99,7 +157,7 @@ func (e *tabLip) onCaptionKeyUp(evt *Event) {
 }
 
 func (e *tabLip) closeTab() {
-  e.tabbed.closeTab(e)
+  e.tabbed.requestClose(e)
 }
 
 func (e *tabLip) isActive() bool {
@@ -12,6 +12,7 @@ func (e *tabPage) A(children ...Element) Container {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -32,13 +33,13 @@ func (e *tabPage) Padding(p ...int) Container {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
 func (e *tabPage) Spacing(s int) Container {
   e.spacing = s
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -23,6 +23,7 @@ type Text struct {
   font    string
   size    float64
   color   sdl.Color
+  bidi    bool // false if content is already in visual order
 
   refGlyph *Glyph
 }
@@ -36,7 +37,7 @@ func NewMono(content string, size float64) *Text {
 }
 
 func NewText(content string, font string, size float64) *Text {
-  e := &Text{NewElementData(0, 0), "", font, size, BLACK, nil}
+  e := &Text{NewElementData(0, 0), "", font, size, BLACK, true, nil}
 
   e.refGlyph = e.Root.P2.Glyphs.GetGlyph(fmt.Sprintf("%s:%d", font, 'a')) 
 
@@ -99,7 +100,7 @@ func (e *Text) Show() {
   }
 
   i := 0
-  for _, c := range []rune(e.content) {
+  for _, c := range e.visualRunes() {
     if isWhitespace(c) {
       continue
     } else {
@@ -111,6 +112,19 @@ func (e *Text) Show() {
       i++
     }
   }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// glyphs are placed left to right in this order
+func (e *Text) visualRunes() []rune {
+  runes := []rune(e.content)
+
+  if e.bidi {
+    return bidiReorder(runes, isRTL())
+  } else {
+    return runes
+  }
 }
 
 func isWhitespace(r rune) bool {
@@ -137,7 +151,7 @@ func (e *Text) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
   space := math.Ceil(e.refGlyph.Advance*e.size/float64(GlyphResolution))
 
   i := 0
-  runes := []rune(e.content)
+  runes := e.visualRunes()
   for _, c := range runes {
     if isWhitespace(c) {
       x += space
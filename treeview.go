@@ -0,0 +1,666 @@
+package glui
+
+import (
+)
+
+//go:generate ./gen_element TreeView "CalcDepth appendChild On Size Padding"
+
+const (
+  TREE_INDENT     = 16
+  TREE_ARROW_SIZE = 10
+  TREE_ARROW_GAP  = 4
+)
+
+type TreeNode struct {
+  tree     *TreeView
+  parent   *TreeNode
+  children []*TreeNode
+
+  caption string
+  Data    interface{} // free to use
+
+  lazy     bool // children are added by the OnLoad callback when expanded for the first time
+  loaded   bool
+  expanded bool
+  selected bool
+
+  arrow *Icon
+  text  *Text
+}
+
+// the treeview itself is styled like an input
+type TreeView struct {
+  ElementData
+
+  roots     []*TreeNode
+  rows      []*TreeNode // nodes whose ancestors are all expanded, in display order
+  rowsDirty bool        // rows are rebuilt once before they are used, so adding many nodes stays linear
+
+  multi   bool
+  pivot   *TreeNode // last selected node, moved by the arrow keys
+  anchor  *TreeNode // start of shift selection
+  showSel bool
+  scroll  int // first visible row
+
+  onLoad   func(n *TreeNode)
+  onSelect func(n *TreeNode)
+}
+
+func NewTreeView() *TreeView {
+  e := &TreeView{
+    NewElementData(9*2, 0), // after the first 18 tris come the sel tris
+    make([]*TreeNode, 0),
+    make([]*TreeNode, 0),
+    false,
+    false,
+    nil, nil,
+    false,
+    0,
+    nil, nil,
+  }
+
+  e.width, e.height = 400, 400
+
+  e.setTypesAndTCoords()
+
+  e.On("click",       e.onMouseClick)
+  e.On("doubleclick", e.onDoubleClick)
+  e.On("wheel",       e.onWheel)
+  e.On("focus",       e.onFocus)
+  e.On("blur",        e.onBlur)
+  e.On("keypress",    e.onKeyPress)
+
+  return e
+}
+
+func (e *TreeView) setTypesAndTCoords() {
+  e.Root.P1.setInputLikeElementTypesAndTCoords(e.p1Tris)
+}
+
+func (e *TreeView) borderT() int {
+  return e.Root.P1.Skin.InputBorderThickness()
+}
+
+func (e *TreeView) lineHeight() int {
+  return 25
+}
+
+// allow selecting multiple nodes with ctrl and shift
+func (e *TreeView) MultiSelect(b bool) *TreeView {
+  e.multi = b
+
+  return e
+}
+
+// called when a lazy node is expanded for the first time
+func (e *TreeView) OnLoad(fn func(n *TreeNode)) *TreeView {
+  e.onLoad = fn
+
+  return e
+}
+
+// called when the user selects a node
+func (e *TreeView) OnSelect(fn func(n *TreeNode)) *TreeView {
+  e.onSelect = fn
+
+  return e
+}
+
+func (e *TreeView) AddNode(caption string) *TreeNode {
+  n := newTreeNode(e, nil, caption)
+
+  e.roots = append(e.roots, n)
+
+  e.syncRows()
+
+  return n
+}
+
+func (e *TreeView) Roots() []*TreeNode {
+  return e.roots
+}
+
+func (e *TreeView) Selected() []*TreeNode {
+  res := make([]*TreeNode, 0)
+
+  var collect func(nodes []*TreeNode)
+  collect = func(nodes []*TreeNode) {
+    for _, n := range nodes {
+      if n.selected {
+        res = append(res, n)
+      }
+
+      collect(n.children)
+    }
+  }
+
+  collect(e.roots)
+
+  return res
+}
+
+func newTreeNode(tree *TreeView, parent *TreeNode, caption string) *TreeNode {
+  n := &TreeNode{
+    tree, parent, make([]*TreeNode, 0),
+    caption, nil,
+    false, false, false, false,
+    NewIcon("arrow-down-drop", TREE_ARROW_SIZE),
+    NewSans(caption, 10),
+  }
+
+  tree.appendChild(n.arrow, n.text)
+
+  // shown by the next updateRows if the node is visible
+  n.arrow.Hide()
+  n.text.Hide()
+
+  return n
+}
+
+func (n *TreeNode) AddNode(caption string) *TreeNode {
+  child := newTreeNode(n.tree, n, caption)
+
+  n.children = append(n.children, child)
+
+  n.tree.syncRows()
+
+  return child
+}
+
+// the node shows an expand arrow, but its children are only added when the OnLoad callback is called
+func (n *TreeNode) SetLazy() *TreeNode {
+  n.lazy = true
+  n.loaded = false
+
+  n.tree.syncRows()
+
+  return n
+}
+
+func (n *TreeNode) Caption() string {
+  return n.caption
+}
+
+func (n *TreeNode) Parent() *TreeNode {
+  return n.parent
+}
+
+func (n *TreeNode) Children() []*TreeNode {
+  return n.children
+}
+
+func (n *TreeNode) Expanded() bool {
+  return n.expanded
+}
+
+func (n *TreeNode) Selected() bool {
+  return n.selected
+}
+
+func (n *TreeNode) hasChildren() bool {
+  return len(n.children) > 0 || (n.lazy && !n.loaded)
+}
+
+func (n *TreeNode) depth() int {
+  d := 0
+
+  for p := n.parent; p != nil; p = p.parent {
+    d++
+  }
+
+  return d
+}
+
+func (n *TreeNode) Expand() {
+  if n.lazy && !n.loaded {
+    n.loaded = true
+
+    if n.tree.onLoad != nil {
+      n.tree.onLoad(n)
+    }
+  }
+
+  n.expanded = true
+
+  n.tree.syncRows()
+}
+
+func (n *TreeNode) Collapse() {
+  n.expanded = false
+
+  // the pivot and anchor can't be hidden
+  if n.tree.pivot != nil && n.tree.pivot.hasAncestor(n) {
+    n.tree.pivot = n
+  }
+
+  if n.tree.anchor != nil && n.tree.anchor.hasAncestor(n) {
+    n.tree.anchor = n
+  }
+
+  n.tree.syncRows()
+}
+
+func (n *TreeNode) Toggle() {
+  if n.expanded {
+    n.Collapse()
+  } else {
+    n.Expand()
+  }
+}
+
+func (n *TreeNode) hasAncestor(a *TreeNode) bool {
+  for p := n.parent; p != nil; p = p.parent {
+    if p == a {
+      return true
+    }
+  }
+
+  return false
+}
+
+func (e *TreeView) rowIndex(n *TreeNode) int {
+  e.updateRows()
+
+  for i, row := range e.rows {
+    if row == n {
+      return i
+    }
+  }
+
+  return -1
+}
+
+func (e *TreeView) syncRows() {
+  e.rowsDirty = true
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+// visibility of arrows and captions follows the expansion state of the nodes
+func (e *TreeView) updateRows() {
+  if !e.rowsDirty {
+    return
+  }
+
+  e.rowsDirty = false
+
+  e.rows = make([]*TreeNode, 0)
+
+  var walk func(nodes []*TreeNode, visible bool)
+  walk = func(nodes []*TreeNode, visible bool) {
+    for _, n := range nodes {
+      if visible && e.Visible() {
+        e.rows = append(e.rows, n)
+
+        n.text.Show()
+
+        if !n.hasChildren() {
+          n.arrow.Hide()
+        } else {
+          n.arrow.Show()
+
+          if n.expanded {
+            n.arrow.ChangeGlyph("arrow-down-drop")
+            n.arrow.SetOrientation(HOR)
+          } else if isRTL() {
+            // transposed up arrow points left
+            n.arrow.ChangeGlyph("arrow-up-drop")
+            n.arrow.SetOrientation(VER)
+          } else {
+            // transposed down arrow points right
+            n.arrow.ChangeGlyph("arrow-down-drop")
+            n.arrow.SetOrientation(VER)
+          }
+        }
+      } else {
+        n.arrow.Hide()
+        n.text.Hide()
+      }
+
+      walk(n.children, visible && n.expanded)
+    }
+  }
+
+  walk(e.roots, true)
+
+  e.syncSelection()
+}
+
+func (e *TreeView) syncSelection() {
+  e.updateRows()
+
+  numSel := 0
+  for _, n := range e.rows {
+    if n.selected {
+      numSel++
+    }
+  }
+
+  // sel tris are resized in place after the 18 border tris
+  selTris := e.Root.P1.Resize(e.p1Tris[18:], numSel*2)
+  e.p1Tris = append(e.p1Tris[0:18], selTris...)
+
+  for _, tri := range selTris {
+    if e.showSel {
+      e.Root.P1.SetTriType(tri, VTYPE_PLAIN)
+      e.Root.P1.SetColorConst(tri, e.Root.P1.Skin.SelColor())
+    } else {
+      e.Root.P1.SetTriType(tri, VTYPE_HIDDEN)
+    }
+  }
+
+  for _, n := range e.rows {
+    if n.selected && e.showSel {
+      n.text.SetColor(WHITE)
+    } else {
+      n.text.SetColor(BLACK)
+    }
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *TreeView) Show() {
+  e.ElementData.Show()
+
+  e.syncRows()
+}
+
+func (e *TreeView) nVisibleRows(h int) int {
+  return (h - 2*e.borderT())/e.lineHeight()
+}
+
+func (e *TreeView) clampScroll(h int) {
+  maxScroll := len(e.rows) - e.nVisibleRows(h)
+  if e.scroll > maxScroll {
+    e.scroll = maxScroll
+  }
+
+  if e.scroll < 0 {
+    e.scroll = 0
+  }
+}
+
+// x range of the expand arrow of a row, relative to the treeview
+func (e *TreeView) arrowX(n *TreeNode, w int) int {
+  x := e.borderT() + TREE_ARROW_GAP + n.depth()*TREE_INDENT
+
+  if isRTL() {
+    return w - x - TREE_ARROW_SIZE
+  } else {
+    return x
+  }
+}
+
+func (e *TreeView) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
+  e.updateRows()
+
+  w, h := e.GetSize()
+
+  if w > maxWidth || w < 0 {
+    w = maxWidth
+  }
+
+  if h > maxHeight || h < 0 {
+    h = maxHeight
+  }
+
+  t := e.borderT()
+  lh := e.lineHeight()
+
+  e.clampScroll(h)
+
+  inner := Rect{t, t, w - 2*t, h - 2*t}
+
+  // sel quads lie between the background and the rows
+  zSel := (normalizeZIndex(e.zIndex, maxZIndex) + normalizeZIndex(e.zIndex + 1, maxZIndex))*0.5
+
+  selI := 0
+  for i, n := range e.rows {
+    y := t + (i - e.scroll)*lh
+
+    aw, ah := calcPos(n.arrow, TREE_ARROW_SIZE, lh, maxZIndex)
+    ax := e.arrowX(n, w)
+    n.arrow.Translate(ax, y + (lh - ah)/2)
+
+    textMaxW := w - t - (e.borderT() + TREE_ARROW_GAP + n.depth()*TREE_INDENT + aw + TREE_ARROW_GAP)
+    tw, th := calcPos(n.text, textMaxW, lh, maxZIndex)
+    if isRTL() {
+      n.text.Translate(ax - TREE_ARROW_GAP - tw, y + (lh - th)/2)
+    } else {
+      n.text.Translate(ax + aw + TREE_ARROW_GAP, y + (lh - th)/2)
+    }
+
+    n.arrow.Crop(inner)
+    n.text.Crop(inner)
+
+    if n.selected {
+      tri0 := e.p1Tris[18 + selI*2]
+      tri1 := e.p1Tris[18 + selI*2 + 1]
+
+      r := Rect{t, y, w - 2*t, lh}.Common(inner)
+
+      e.Root.P1.SetQuadPos(tri0, tri1, r, zSel)
+
+      selI++
+    }
+  }
+
+  e.SetBorderedElementPos(w, h, t, maxZIndex)
+
+  return e.InitRect(w, h)
+}
+
+// returns nil if there is no row at that position
+func (e *TreeView) nodeAt(evt *Event) *TreeNode {
+  e.updateRows()
+
+  _, y := evt.RelPos(e.Rect())
+
+  if y < e.borderT() {
+    return nil
+  }
+
+  i := (y - e.borderT())/e.lineHeight() + e.scroll
+  if i < 0 || i >= len(e.rows) {
+    return nil
+  }
+
+  return e.rows[i]
+}
+
+func (e *TreeView) onMouseClick(evt *Event) {
+  n := e.nodeAt(evt)
+  if n == nil {
+    return
+  }
+
+  x, _ := evt.RelPos(e.Rect())
+  ax := e.arrowX(n, e.Rect().W)
+
+  if n.hasChildren() && x >= ax - TREE_ARROW_GAP && x < ax + TREE_ARROW_SIZE + TREE_ARROW_GAP {
+    n.Toggle()
+  } else if e.multi && evt.Shift && e.anchor != nil {
+    e.selectRange(n)
+  } else {
+    e.selectNode(n, e.multi && evt.Ctrl)
+  }
+}
+
+func (e *TreeView) onDoubleClick(evt *Event) {
+  n := e.nodeAt(evt)
+
+  if n != nil && n.hasChildren() {
+    n.Toggle()
+  }
+}
+
+func (e *TreeView) onWheel(evt *Event) {
+  if evt.YRel > 0 {
+    e.scroll++
+  } else if evt.YRel < 0 {
+    e.scroll--
+  }
+
+  e.Root.ForceElementPosDirty(e)
+}
+
+func (e *TreeView) onFocus(evt *Event) {
+  if evt.IsKeyboardEvent() {
+    e.Root.FocusRect.Show(e)
+  }
+
+  e.showSel = true
+
+  e.syncSelection()
+}
+
+func (e *TreeView) onBlur(evt *Event) {
+  e.Root.FocusRect.Hide()
+
+  e.showSel = false
+
+  e.syncSelection()
+}
+
+func (e *TreeView) clearSelection() {
+  for _, n := range e.Selected() {
+    n.selected = false
+  }
+}
+
+// toggles the node if keepOld, otherwise the node becomes the only selected node
+func (e *TreeView) selectNode(n *TreeNode, keepOld bool) {
+  if keepOld {
+    n.selected = !n.selected
+  } else {
+    e.clearSelection()
+
+    n.selected = true
+  }
+
+  e.anchor = n
+
+  e.setPivot(n)
+}
+
+// selects all rows between the anchor and n
+func (e *TreeView) selectRange(n *TreeNode) {
+  i0, i1 := e.rowIndex(e.anchor), e.rowIndex(n)
+  if i0 < 0 {
+    i0 = i1
+  }
+
+  if i0 > i1 {
+    i0, i1 = i1, i0
+  }
+
+  e.clearSelection()
+
+  for i := i0; i <= i1; i++ {
+    e.rows[i].selected = true
+  }
+
+  e.setPivot(n)
+}
+
+func (e *TreeView) setPivot(n *TreeNode) {
+  e.pivot = n
+
+  // keep the pivot in view
+  i := e.rowIndex(n)
+  nVisible := e.nVisibleRows(e.rect.H)
+  if i < e.scroll {
+    e.scroll = i
+  } else if i >= e.scroll + nVisible {
+    e.scroll = i - nVisible + 1
+  }
+
+  e.syncSelection()
+
+  if e.onSelect != nil {
+    e.onSelect(n)
+  }
+}
+
+func (e *TreeView) moveCursor(i int, shift bool) {
+  e.updateRows()
+
+  if len(e.rows) == 0 {
+    return
+  }
+
+  if i < 0 {
+    i = 0
+  } else if i >= len(e.rows) {
+    i = len(e.rows) - 1
+  }
+
+  if e.multi && shift && e.anchor != nil {
+    e.selectRange(e.rows[i])
+  } else {
+    e.selectNode(e.rows[i], false)
+  }
+}
+
+func (e *TreeView) onKeyPress(evt *Event) {
+  e.updateRows()
+
+  cur := -1
+  if e.pivot != nil {
+    cur = e.rowIndex(e.pivot)
+  }
+
+  key := evt.Key
+  if isRTL() {
+    switch key {
+    case "left":
+      key = "right"
+    case "right":
+      key = "left"
+    }
+  }
+
+  switch key {
+  case "down":
+    e.moveCursor(cur + 1, evt.Shift)
+  case "up":
+    if cur == -1 {
+      cur = len(e.rows)
+    }
+
+    e.moveCursor(cur - 1, evt.Shift)
+  case "home":
+    e.moveCursor(0, evt.Shift)
+  case "end":
+    e.moveCursor(len(e.rows) - 1, evt.Shift)
+  case "pagedown":
+    e.moveCursor(cur + e.nVisibleRows(e.rect.H), evt.Shift)
+  case "pageup":
+    e.moveCursor(cur - e.nVisibleRows(e.rect.H), evt.Shift)
+  case "right":
+    if e.pivot != nil && e.pivot.hasChildren() {
+      if !e.pivot.expanded {
+        e.pivot.Expand()
+      } else if len(e.pivot.children) > 0 {
+        e.selectNode(e.pivot.children[0], false)
+      }
+    }
+  case "left":
+    if e.pivot != nil {
+      if e.pivot.expanded {
+        e.pivot.Collapse()
+      } else if e.pivot.parent != nil {
+        e.selectNode(e.pivot.parent, false)
+      }
+    }
+  case "return":
+    if e.pivot != nil && e.pivot.hasChildren() {
+      e.pivot.Toggle()
+    }
+  case "space":
+    if e.pivot != nil {
+      e.selectNode(e.pivot, e.multi && evt.Ctrl)
+    }
+  }
+}
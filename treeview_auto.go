@@ -0,0 +1,55 @@
+package glui
+func (e *TreeView) CalcDepth(stack *ElementStack) {
+  e.zIndex = stack.Add(e, e.closerThan)
+  for _, child := range e.Children() {
+    child.CalcDepth(stack)
+  }
+}
+
+func (e *TreeView) appendChild(children ...Element) Element {
+  for _, child := range children {
+    e.children = append(e.children, child)
+    child.RegisterParent(e)
+  }
+  e.Root.ForceElementDepthDirty(e)
+  return e
+}
+
+func (e *TreeView) On(name string, fn EventListener) *TreeView {
+  old := e.evtListeners[name]
+  if old == nil {
+    e.evtListeners[name] = fn
+  } else {
+    e.evtListeners[name] = func(evt *Event) {fn(evt); if !evt.stopPropagation {old(evt)}}
+  }
+  return e
+}
+
+func (e *TreeView) Size(w, h int) *TreeView {
+  e.width = w
+  e.height = h
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
+func (e *TreeView) Padding(p ...int) *TreeView {
+  switch len(p) {
+  case 1:
+    e.padding = [4]int{p[0], p[0], p[0], p[0]}
+    break
+  case 2:
+    e.padding = [4]int{p[0], p[1], p[0], p[1]}
+    break
+  case 3:
+    e.padding = [4]int{p[0], p[1], p[0], p[2]}
+    break
+  case 4:
+    e.padding = [4]int{p[0], p[1], p[2], p[3]}
+    break
+  default:
+    panic("unexpected number of padding elements")
+  }
+  e.Root.ForceElementPosDirty(e)
+  return e
+}
+
@@ -42,7 +42,7 @@ func (e *Ver) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
       y += e.spacing
     }
 
-    childW, childH := child.CalcPos(
+    childW, childH := calcPos(child,
       maxWidth - e.padding[1] - e.padding[3],
       maxHeight - y - e.padding[2],
       maxZIndex)
@@ -93,11 +93,16 @@ func (e *Ver) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
     w = maxChildW + e.padding[1] + e.padding[3]
   }
 
-  if someDYSet || e.hAlign != START {
+  hAlign := e.hAlign
+  if isRTL() {
+    hAlign = mirrorAlign(hAlign)
+  }
+
+  if someDYSet || hAlign != START {
     for i, child := range e.children {
       dx := 0
 
-      switch e.hAlign {
+      switch hAlign {
       case CENTER:
         dx = (w - childWs[i] - e.padding[1] - e.padding[3])/2
       case END:
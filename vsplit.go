@@ -151,7 +151,7 @@ func (e *VSplit) onMouseUp(evt *Event) {
 func (e *VSplit) moveActiveBar(delta int) {
   e.setInterval(e.activeBar, e.startLeft + delta, e.startRight - delta)
 
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
 }
 
 func (e *VSplit) childSpacing() int {
@@ -263,7 +263,7 @@ func (e *VSplit) CalcPos(maxWidth, maxHeight, maxZIndex int) (int, int) {
       e.intervals[i] = w
     }
 
-    _, h := child.CalcPos(w, maxHeight - e.padding[0] - e.padding[2], maxZIndex)
+    _, h := calcPos(child, w, maxHeight - e.padding[0] - e.padding[2], maxZIndex)
     if h > cHeight {
       cHeight = h
     }
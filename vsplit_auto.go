@@ -5,6 +5,7 @@ func (e *VSplit) A(children ...Element) Element {
     e.children = append(e.children, child)
     child.RegisterParent(e)
   }
+  e.Root.ForceElementDepthDirty(e)
   return e
 }
 
@@ -17,7 +18,7 @@ func (e *VSplit) CalcDepth(stack *ElementStack) {
 
 func (e *VSplit) Spacing(s int) *VSplit {
   e.spacing = s
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 
@@ -38,7 +39,7 @@ func (e *VSplit) Padding(p ...int) *VSplit {
   default:
     panic("unexpected number of padding elements")
   }
-  e.Root.ForcePosDirty()
+  e.Root.ForceElementPosDirty(e)
   return e
 }
 